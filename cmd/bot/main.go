@@ -23,6 +23,7 @@ func main() {
 	discordToken := os.Getenv("DISCORD_BOT_TOKEN")
 	databaseURL := os.Getenv("DATABASE_URL")
 	atcoderAPIBaseURL := os.Getenv("ATCODER_API_BASE_URL")
+	syncMode := os.Getenv("SUBMISSION_SYNC_MODE")
 
 	if discordToken == "" {
 		log.Fatal("DISCORD_BOT_TOKEN is required")
@@ -76,7 +77,7 @@ func main() {
 
 	// Create and start scheduler
 	log.Println("Starting scheduler...")
-	sched := scheduler.New(db, discordBot.Session, atcoderClient)
+	sched := scheduler.New(db, discordBot.Session, atcoderClient, syncMode)
 	if err := sched.Start(); err != nil {
 		log.Fatalf("Failed to start scheduler: %v", err)
 	}
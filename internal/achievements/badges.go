@@ -0,0 +1,94 @@
+package achievements
+
+import (
+	"time"
+
+	"coding-winner/internal/database/queries"
+)
+
+// Badge is an achievement unlocked when its rule is satisfied
+type Badge struct {
+	Key         string
+	Emoji       string
+	Name        string
+	Description string
+	Rule        Rule
+}
+
+// Badges lists every badge. Add new badges here; the scheduler picks them up automatically.
+var Badges = []Badge{
+	{
+		Key:         "first-ac",
+		Emoji:       "🌱",
+		Name:        "はじめの一歩",
+		Description: "初めてACする",
+		Rule:        ACCount{N: 1},
+	},
+	{
+		Key:         "ac-100",
+		Emoji:       "💯",
+		Name:        "100AC",
+		Description: "100問ACする",
+		Rule:        ACCount{N: 100},
+	},
+	{
+		Key:         "first-yellow",
+		Emoji:       "🟡",
+		Name:        "黄色の壁",
+		Description: "難易度2000以上の問題を初めてACする",
+		Rule:        SolvedDifficulty{Min: 2000},
+	},
+	{
+		Key:         "streak-30",
+		Emoji:       "🔥",
+		Name:        "30日連続精進",
+		Description: "30日連続で新規ACする",
+		Rule:        Streak{Days: 30},
+	},
+	{
+		Key:         "abc-a-to-d-week",
+		Emoji:       "🏃",
+		Name:        "ABC A〜D制覇",
+		Description: "1週間以内にABCのA〜Dをすべて解く",
+		Rule: ContestSet{
+			ContestPrefix: "abc",
+			Letters:       []string{"a", "b", "c", "d"},
+			Within:        7 * 24 * time.Hour,
+		},
+	},
+}
+
+// FindBadge looks up a badge by key
+func FindBadge(key string) (Badge, bool) {
+	for _, b := range Badges {
+		if b.Key == key {
+			return b, true
+		}
+	}
+	return Badge{}, false
+}
+
+// Unlock is a badge a user has earned, with when they earned it
+type Unlock struct {
+	Badge      Badge
+	AchievedAt time.Time
+}
+
+// Evaluate returns the badges whose rules the user newly satisfies,
+// skipping those whose keys are already in unlocked
+func Evaluate(db queries.UserDB, userID string, unlocked map[string]bool) ([]Unlock, error) {
+	var newlyUnlocked []Unlock
+	for _, badge := range Badges {
+		if unlocked[badge.Key] {
+			continue
+		}
+		achievedAt, ok, err := badge.Rule.AchievedAt(db, userID)
+		if err != nil {
+			return newlyUnlocked, err
+		}
+		if ok {
+			newlyUnlocked = append(newlyUnlocked, Unlock{Badge: badge, AchievedAt: achievedAt})
+		}
+	}
+	return newlyUnlocked, nil
+}
@@ -0,0 +1,62 @@
+package achievements
+
+import (
+	"database/sql"
+	"time"
+
+	"coding-winner/internal/database/queries"
+)
+
+// Rule is a condition a user must satisfy to unlock a badge
+type Rule interface {
+	// AchievedAt returns when the user first satisfied the rule, and false if they have not
+	AchievedAt(db queries.UserDB, userID string) (time.Time, bool, error)
+}
+
+// achieved unpacks a nullable achievement time returned by a query
+func achieved(t sql.NullTime, err error) (time.Time, bool, error) {
+	return t.Time, t.Valid, err
+}
+
+// ACCount is satisfied once the user has solved at least N distinct problems
+type ACCount struct {
+	N int
+}
+
+// AchievedAt implements Rule
+func (r ACCount) AchievedAt(db queries.UserDB, userID string) (time.Time, bool, error) {
+	return achieved(queries.GetNthFirstACTime(db, userID, r.N))
+}
+
+// SolvedDifficulty is satisfied once the user has solved a problem with difficulty >= Min
+type SolvedDifficulty struct {
+	Min int
+}
+
+// AchievedAt implements Rule
+func (r SolvedDifficulty) AchievedAt(db queries.UserDB, userID string) (time.Time, bool, error) {
+	return achieved(queries.GetFirstDifficultyACTime(db, userID, r.Min))
+}
+
+// Streak is satisfied once the user has had new ACs on Days consecutive days
+type Streak struct {
+	Days int
+}
+
+// AchievedAt implements Rule
+func (r Streak) AchievedAt(db queries.UserDB, userID string) (time.Time, bool, error) {
+	return achieved(queries.GetStreakReachedTime(db, userID, r.Days))
+}
+
+// ContestSet is satisfied once the user has solved every listed task of a single
+// contest whose ID starts with ContestPrefix, all within the Within window
+type ContestSet struct {
+	ContestPrefix string
+	Letters       []string
+	Within        time.Duration
+}
+
+// AchievedAt implements Rule
+func (r ContestSet) AchievedAt(db queries.UserDB, userID string) (time.Time, bool, error) {
+	return achieved(queries.GetContestSetCompletedTime(db, userID, r.ContestPrefix, r.Letters, r.Within))
+}
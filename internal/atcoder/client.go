@@ -1,6 +1,7 @@
 package atcoder
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
@@ -24,10 +25,25 @@ func NewClient(baseURL string) *Client {
 	}
 }
 
+// AtCoderBaseURL is the base URL of AtCoder itself (as opposed to AtCoder Problems)
+const AtCoderBaseURL = "https://atcoder.jp"
+
 // get performs a GET request to the API
 func (c *Client) get(endpoint string) ([]byte, error) {
-	url := fmt.Sprintf("%s%s", c.BaseURL, endpoint)
+	return c.getURL(fmt.Sprintf("%s%s", c.BaseURL, endpoint))
+}
+
+// StatusError is returned when a request gets a response other than 200 OK
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d", e.StatusCode)
+}
 
+// getURL performs a GET request to an absolute URL
+func (c *Client) getURL(url string) ([]byte, error) {
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -43,7 +59,7 @@ func (c *Client) get(endpoint string) ([]byte, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, &StatusError{StatusCode: resp.StatusCode}
 	}
 
 	body, err := ioutil.ReadAll(resp.Body)
@@ -59,15 +75,14 @@ func (c *Client) RateLimitDelay() {
 	time.Sleep(1 * time.Second)
 }
 
-// checkUserExists verifies if a user exists on AtCoder
+// CheckUserExists verifies if a user exists on AtCoder by looking up their profile page
 func (c *Client) CheckUserExists(username string) (bool, error) {
-	// Get user's submissions to check if they exist
-	submissions, err := c.GetUserSubmissions(username, 1)
-	if err != nil {
-		// If we can't get submissions, try the user info endpoint
+	_, err := c.GetAffiliation(username)
+	if errors.Is(err, ErrUserNotFound) {
 		return false, nil
 	}
-
-	// If we got submissions or an empty array, the user exists
-	return submissions != nil, nil
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
@@ -1,6 +1,7 @@
 package atcoder
 
 import (
+	"database/sql"
 	"encoding/json"
 	"fmt"
 	"regexp"
@@ -19,6 +20,117 @@ type ContestResponse struct {
 	RateChange       string `json:"rate_change"`
 }
 
+// ContestProblemResponse represents a contest-problem pair from AtCoder Problems API
+type ContestProblemResponse struct {
+	ContestID    string `json:"contest_id"`
+	ProblemID    string `json:"problem_id"`
+	ProblemIndex string `json:"problem_index"`
+}
+
+// MergedProblemResponse represents a problem with its point value from AtCoder Problems API
+type MergedProblemResponse struct {
+	ID    string   `json:"id"`
+	Point *float64 `json:"point"`
+}
+
+// GetAllContests retrieves all past and upcoming contests
+func (c *Client) GetAllContests() ([]*ContestResponse, error) {
+	body, err := c.get("/resources/contests.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var contests []*ContestResponse
+	if err := json.Unmarshal(body, &contests); err != nil {
+		return nil, fmt.Errorf("failed to parse contests: %w", err)
+	}
+	return contests, nil
+}
+
+// GetAllContestProblems retrieves the problem sets of all contests
+func (c *Client) GetAllContestProblems() ([]*ContestProblemResponse, error) {
+	body, err := c.get("/resources/contest-problem.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var pairs []*ContestProblemResponse
+	if err := json.Unmarshal(body, &pairs); err != nil {
+		return nil, fmt.Errorf("failed to parse contest problems: %w", err)
+	}
+	return pairs, nil
+}
+
+// GetProblemPoints retrieves the point value of every problem that has one
+func (c *Client) GetProblemPoints() (map[string]float64, error) {
+	body, err := c.get("/resources/merged-problems.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []*MergedProblemResponse
+	if err := json.Unmarshal(body, &problems); err != nil {
+		return nil, fmt.Errorf("failed to parse merged problems: %w", err)
+	}
+
+	points := make(map[string]float64)
+	for _, p := range problems {
+		if p.Point != nil {
+			points[p.ID] = *p.Point
+		}
+	}
+	return points, nil
+}
+
+// SyncContests retrieves all contests with their problem sets and point values
+func (c *Client) SyncContests() ([]*models.Contest, []*models.ContestProblem, error) {
+	apiContests, err := c.GetAllContests()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pairs, err := c.GetAllContestProblems()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	points, err := c.GetProblemPoints()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contests := make([]*models.Contest, 0, len(apiContests))
+	known := make(map[string]bool, len(apiContests))
+	for _, ac := range apiContests {
+		contests = append(contests, &models.Contest{
+			ContestID:       ac.ID,
+			Title:           ac.Title,
+			StartTime:       time.Unix(ac.StartEpochSecond, 0),
+			DurationSeconds: int(ac.DurationSeconds),
+			RateChange:      sql.NullString{String: ac.RateChange, Valid: ac.RateChange != ""},
+		})
+		known[ac.ID] = true
+	}
+
+	contestProblems := make([]*models.ContestProblem, 0, len(pairs))
+	for _, pair := range pairs {
+		if !known[pair.ContestID] {
+			continue
+		}
+		cp := &models.ContestProblem{
+			ContestID:    pair.ContestID,
+			ProblemID:    pair.ProblemID,
+			ProblemIndex: pair.ProblemIndex,
+		}
+		if point, ok := points[pair.ProblemID]; ok {
+			cp.Point = sql.NullFloat64{Float64: point, Valid: true}
+		}
+		contestProblems = append(contestProblems, cp)
+	}
+
+	return contests, contestProblems, nil
+}
+
 // GetUpcomingContests retrieves upcoming contests
 func (c *Client) GetUpcomingContests() ([]*models.AtCoderContest, error) {
 	endpoint := "/atcoder-api/v3/contests"
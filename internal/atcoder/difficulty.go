@@ -0,0 +1,55 @@
+package atcoder
+
+import (
+	"math"
+)
+
+// Prior used when estimating a user's ability from few attempts
+const (
+	ratingPriorMean   = 800.0
+	ratingPriorStdDev = 1000.0
+)
+
+// IRTAttempt is a single observation used for ability estimation
+type IRTAttempt struct {
+	Difficulty     float64
+	Discrimination float64
+	Solved         bool
+}
+
+// PredictSolveProbability returns the probability that a user with the given
+// rating solves a problem, following the AtCoder Problems IRT model
+func PredictSolveProbability(rating, difficulty, discrimination float64) float64 {
+	return 1 / (1 + math.Exp(-discrimination*(rating-difficulty)))
+}
+
+// EstimateRating estimates a user's ability from their solved and unsolved attempts.
+// It returns the maximum a posteriori rating under a weak normal prior, so users
+// with no failed attempts still get a finite estimate.
+func EstimateRating(attempts []IRTAttempt) float64 {
+	// The derivative of the log posterior is strictly decreasing in the rating,
+	// so its root can be found by bisection
+	derivative := func(rating float64) float64 {
+		d := -(rating - ratingPriorMean) / (ratingPriorStdDev * ratingPriorStdDev)
+		for _, a := range attempts {
+			p := PredictSolveProbability(rating, a.Difficulty, a.Discrimination)
+			if a.Solved {
+				d += a.Discrimination * (1 - p)
+			} else {
+				d -= a.Discrimination * p
+			}
+		}
+		return d
+	}
+
+	lo, hi := -2000.0, 6000.0
+	for iter := 0; iter < 60; iter++ {
+		mid := (lo + hi) / 2
+		if derivative(mid) > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2
+}
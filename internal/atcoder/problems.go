@@ -15,11 +15,14 @@ type ProblemResponse struct {
 	Title     string `json:"title"`
 }
 
-// ProblemDifficulty represents problem difficulty from AtCoder Problems API
+// ProblemDifficulty represents a problem model from AtCoder Problems API
 type ProblemDifficulty struct {
-	ProblemID  string  `json:"problem_id"`
-	Difficulty *int    `json:"difficulty"`
-	IsExperimental bool `json:"is_experimental"`
+	ProblemID      string   `json:"problem_id"`
+	Difficulty     *int     `json:"difficulty"`
+	Slope          *float64 `json:"slope"`
+	Intercept      *float64 `json:"intercept"`
+	Discrimination *float64 `json:"discrimination"`
+	IsExperimental bool     `json:"is_experimental"`
 }
 
 // GetAllProblems retrieves all problems
@@ -39,8 +42,8 @@ func (c *Client) GetAllProblems() ([]*ProblemResponse, error) {
 	return problems, nil
 }
 
-// GetProblemDifficulties retrieves problem difficulties
-func (c *Client) GetProblemDifficulties() (map[string]*int, error) {
+// GetProblemDifficulties retrieves non-experimental problem models keyed by problem ID
+func (c *Client) GetProblemDifficulties() (map[string]*ProblemDifficulty, error) {
 	endpoint := "/resources/problem-models.json"
 
 	body, err := c.get(endpoint)
@@ -54,11 +57,11 @@ func (c *Client) GetProblemDifficulties() (map[string]*int, error) {
 		return nil, fmt.Errorf("failed to parse difficulties: %w", err)
 	}
 
-	// Create map of problem_id -> difficulty
-	diffMap := make(map[string]*int)
+	// Keep only models with a stable difficulty
+	diffMap := make(map[string]*ProblemDifficulty)
 	for problemID, d := range difficultiesMap {
 		if d != nil && !d.IsExperimental && d.Difficulty != nil {
-			diffMap[problemID] = d.Difficulty
+			diffMap[problemID] = d
 		}
 	}
 
@@ -87,19 +90,28 @@ func (c *Client) SyncProblems() ([]*models.Problem, error) {
 			Valid:  apiProb.ContestID != "",
 		}
 
-		difficulty := sql.NullInt64{Valid: false}
-		if diff, ok := difficulties[apiProb.ID]; ok && diff != nil {
-			difficulty.Int64 = int64(*diff)
-			difficulty.Valid = true
+		problem := &models.Problem{
+			ProblemID: apiProb.ID,
+			ContestID: contestID,
+			Title:     apiProb.Title,
+		}
+		if model, ok := difficulties[apiProb.ID]; ok {
+			problem.Difficulty = sql.NullInt64{Int64: int64(*model.Difficulty), Valid: true}
+			problem.Slope = toNullFloat64(model.Slope)
+			problem.Intercept = toNullFloat64(model.Intercept)
+			problem.Discrimination = toNullFloat64(model.Discrimination)
 		}
 
-		problems = append(problems, &models.Problem{
-			ProblemID:  apiProb.ID,
-			ContestID:  contestID,
-			Title:      apiProb.Title,
-			Difficulty: difficulty,
-		})
+		problems = append(problems, problem)
 	}
 
 	return problems, nil
 }
+
+// toNullFloat64 converts an optional JSON number to sql.NullFloat64
+func toNullFloat64(v *float64) sql.NullFloat64 {
+	if v == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *v, Valid: true}
+}
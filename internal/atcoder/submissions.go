@@ -23,9 +23,18 @@ type SubmissionResponse struct {
 	ExecutionTime *int   `json:"execution_time"`
 }
 
-// GetUserSubmissions retrieves submissions for a user
-func (c *Client) GetUserSubmissions(username string, limit int) ([]*SubmissionResponse, error) {
-	endpoint := fmt.Sprintf("/atcoder-api/v3/user/submissions?user=%s&from_second=0", username)
+// FinalResults are the judge results that do not change unless the submission is rejudged.
+// Anything else, such as WJ, WR or a progress like "3/10", is still being judged.
+var FinalResults = []string{"AC", "WA", "TLE", "MLE", "RE", "CE", "OLE", "IE"}
+
+// SubmissionsPageSize is the largest number of submissions the API returns for one request
+const SubmissionsPageSize = 500
+
+// GetUserSubmissionsSince retrieves submissions for a user since a specific time, oldest first.
+// At most SubmissionsPageSize submissions are returned; fetch the rest with a later time.
+func (c *Client) GetUserSubmissionsSince(username string, since time.Time) ([]*SubmissionResponse, error) {
+	fromSecond := since.Unix()
+	endpoint := fmt.Sprintf("/atcoder-api/v3/user/submissions?user=%s&from_second=%d", username, fromSecond)
 
 	body, err := c.get(endpoint)
 	if err != nil {
@@ -37,18 +46,16 @@ func (c *Client) GetUserSubmissions(username string, limit int) ([]*SubmissionRe
 		return nil, fmt.Errorf("failed to parse submissions: %w", err)
 	}
 
-	// Sort by time descending and limit
-	if len(submissions) > limit && limit > 0 {
-		submissions = submissions[:limit]
-	}
-
 	return submissions, nil
 }
 
-// GetUserSubmissionsSince retrieves submissions for a user since a specific time
-func (c *Client) GetUserSubmissionsSince(username string, since time.Time) ([]*SubmissionResponse, error) {
-	fromSecond := since.Unix()
-	endpoint := fmt.Sprintf("/atcoder-api/v3/user/submissions?user=%s&from_second=%d", username, fromSecond)
+// RecentSubmissionsPageSize is the largest number of submissions the global feed returns for one request
+const RecentSubmissionsPageSize = 1000
+
+// GetRecentSubmissions retrieves the submissions of all users since a specific second, oldest
+// first. At most RecentSubmissionsPageSize submissions are returned.
+func (c *Client) GetRecentSubmissions(fromSecond int64) ([]*SubmissionResponse, error) {
+	endpoint := fmt.Sprintf("/atcoder-api/v3/from/%d", fromSecond)
 
 	body, err := c.get(endpoint)
 	if err != nil {
@@ -63,6 +70,22 @@ func (c *Client) GetUserSubmissionsSince(username string, since time.Time) ([]*S
 	return submissions, nil
 }
 
+// NextFromSecond returns where the page after this one starts. from_second is inclusive,
+// so the next page starts at the last second seen and submissions fetched twice must be
+// ignored when saved. A full page that does not get past its first second moves on by one.
+func NextFromSecond(fromSecond int64, page []*SubmissionResponse, pageSize int) int64 {
+	next := fromSecond
+	for _, sub := range page {
+		if sub.EpochSecond > next {
+			next = sub.EpochSecond
+		}
+	}
+	if next == fromSecond && len(page) >= pageSize {
+		next++
+	}
+	return next
+}
+
 // ConvertToModel converts API submission to database model
 func ConvertSubmissionToModel(sub *SubmissionResponse, discordID string) *models.Submission {
 	contestID := sql.NullString{
@@ -83,18 +106,15 @@ func ConvertSubmissionToModel(sub *SubmissionResponse, discordID string) *models
 	}
 }
 
-// SyncUserSubmissions syncs submissions for a user
+// SyncUserSubmissions syncs submissions for a user since a specific time. Without a time
+// it fetches the first page of the history; full histories are fetched by the backfill.
 func (c *Client) SyncUserSubmissions(atcoderUsername string, discordID string, since *time.Time) ([]*models.Submission, error) {
-	var apiSubmissions []*SubmissionResponse
-	var err error
-
+	from := time.Unix(0, 0)
 	if since != nil {
-		apiSubmissions, err = c.GetUserSubmissionsSince(atcoderUsername, *since)
-	} else {
-		// Initial sync: get last 100 submissions
-		apiSubmissions, err = c.GetUserSubmissions(atcoderUsername, 100)
+		from = *since
 	}
 
+	apiSubmissions, err := c.GetUserSubmissionsSince(atcoderUsername, from)
 	if err != nil {
 		return nil, err
 	}
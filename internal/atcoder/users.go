@@ -0,0 +1,38 @@
+package atcoder
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ContestHistoryEntry represents an entry of a user's contest history on AtCoder
+type ContestHistoryEntry struct {
+	IsRated   bool   `json:"IsRated"`
+	NewRating int    `json:"NewRating"`
+	EndTime   string `json:"EndTime"`
+}
+
+// GetUserRating retrieves the current AtCoder rating of a user.
+// Users who have never taken part in a rated contest have a rating of 0.
+func (c *Client) GetUserRating(username string) (int, error) {
+	url := fmt.Sprintf("%s/users/%s/history/json", AtCoderBaseURL, username)
+
+	body, err := c.getURL(url)
+	if err != nil {
+		return 0, err
+	}
+
+	var history []*ContestHistoryEntry
+	if err := json.Unmarshal(body, &history); err != nil {
+		return 0, fmt.Errorf("failed to parse contest history: %w", err)
+	}
+
+	rating := 0
+	for _, entry := range history {
+		if entry.IsRated {
+			rating = entry.NewRating
+		}
+	}
+
+	return rating, nil
+}
@@ -0,0 +1,67 @@
+package atcoder
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// ErrUserNotFound is returned when an AtCoder user does not exist
+var ErrUserNotFound = errors.New("atcoder user not found")
+
+// ProfileFetcher fetches the affiliation shown on an AtCoder user's profile
+type ProfileFetcher interface {
+	GetAffiliation(username string) (string, error)
+}
+
+// affiliationPattern matches the affiliation row of the profile table on the English profile page
+var affiliationPattern = regexp.MustCompile(`(?s)<th[^>]*>\s*Affiliation\s*</th>\s*<td[^>]*>(.*?)</td>`)
+
+// GetAffiliation fetches a user's profile page on AtCoder and returns their affiliation.
+// It returns ErrUserNotFound if the user does not exist.
+func (c *Client) GetAffiliation(username string) (string, error) {
+	url := fmt.Sprintf("%s/users/%s?lang=en", AtCoderBaseURL, username)
+
+	body, err := c.getURL(url)
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return "", ErrUserNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return parseAffiliation(body), nil
+}
+
+// parseAffiliation extracts the affiliation from a profile page. Users without one have no row.
+func parseAffiliation(body []byte) string {
+	m := affiliationPattern.FindSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	return strings.TrimSpace(html.UnescapeString(string(m[1])))
+}
+
+// NewVerificationToken returns a random token for a member to put in their affiliation
+func NewVerificationToken() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return "cw-" + hex.EncodeToString(b), nil
+}
+
+// VerifyOwnership reports whether the user's affiliation contains the token
+func VerifyOwnership(fetcher ProfileFetcher, username, token string) (bool, error) {
+	affiliation, err := fetcher.GetAffiliation(username)
+	if err != nil {
+		return false, err
+	}
+	return strings.Contains(affiliation, token), nil
+}
@@ -0,0 +1,92 @@
+package atcoder
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+// fakeProfileFetcher serves affiliations from a map instead of AtCoder
+type fakeProfileFetcher struct {
+	affiliations map[string]string
+	err          error
+}
+
+func (f *fakeProfileFetcher) GetAffiliation(username string) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	affiliation, ok := f.affiliations[username]
+	if !ok {
+		return "", ErrUserNotFound
+	}
+	return affiliation, nil
+}
+
+func TestParseAffiliation(t *testing.T) {
+	body, err := os.ReadFile("testdata/profile.html")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := parseAffiliation(body), "AtCoder & Co. cw-1a2b3c4d5e6f"; got != want {
+		t.Errorf("parseAffiliation = %q, want %q", got, want)
+	}
+	if got := parseAffiliation([]byte(`<table class="dl-table"><tr><th>Birth Year</th><td>2000</td></tr></table>`)); got != "" {
+		t.Errorf("parseAffiliation without affiliation = %q, want empty", got)
+	}
+}
+
+func TestVerifyOwnership(t *testing.T) {
+	fetcher := &fakeProfileFetcher{affiliations: map[string]string{
+		"alice": "Example University cw-0123456789ab",
+		"bob":   "Example University",
+	}}
+
+	tests := []struct {
+		username string
+		token    string
+		want     bool
+	}{
+		{"alice", "cw-0123456789ab", true},
+		{"alice", "cw-ffffffffffff", false},
+		{"bob", "cw-0123456789ab", false},
+	}
+	for _, tt := range tests {
+		got, err := VerifyOwnership(fetcher, tt.username, tt.token)
+		if err != nil {
+			t.Errorf("VerifyOwnership(%q, %q): %v", tt.username, tt.token, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("VerifyOwnership(%q, %q) = %v, want %v", tt.username, tt.token, got, tt.want)
+		}
+	}
+
+	if _, err := VerifyOwnership(fetcher, "carol", "cw-0123456789ab"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("VerifyOwnership for unknown user: err = %v, want ErrUserNotFound", err)
+	}
+
+	fetchErr := errors.New("connection reset")
+	failing := &fakeProfileFetcher{err: fetchErr}
+	if _, err := VerifyOwnership(failing, "alice", "cw-0123456789ab"); !errors.Is(err, fetchErr) {
+		t.Errorf("VerifyOwnership with failing fetch: err = %v, want %v", err, fetchErr)
+	}
+}
+
+func TestNewVerificationToken(t *testing.T) {
+	a, err := NewVerificationToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewVerificationToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != len("cw-")+12 || a[:3] != "cw-" {
+		t.Errorf("token %q has unexpected format", a)
+	}
+	if a == b {
+		t.Errorf("tokens are not random: %q", a)
+	}
+}
@@ -0,0 +1,94 @@
+package atcoder
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// virtualContestIDPattern matches the UUID of an AtCoder Problems virtual contest
+var virtualContestIDPattern = regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`)
+
+// VirtualContestResponse represents a virtual contest from the AtCoder Problems internal API
+type VirtualContestResponse struct {
+	Info         VirtualContestInfo           `json:"info"`
+	Problems     []*VirtualContestProblemInfo `json:"problems"`
+	Participants []string                     `json:"participants"`
+}
+
+// VirtualContestInfo holds the definition of an AtCoder Problems virtual contest
+type VirtualContestInfo struct {
+	ID               string `json:"id"`
+	Title            string `json:"title"`
+	Memo             string `json:"memo"`
+	OwnerUserID      string `json:"owner_user_id"`
+	StartEpochSecond int64  `json:"start_epoch_second"`
+	DurationSecond   int64  `json:"duration_second"`
+	Mode             string `json:"mode"`
+	PenaltySecond    int64  `json:"penalty_second"`
+}
+
+// VirtualContestProblemInfo is a problem of an AtCoder Problems virtual contest.
+// Point is set only when the contest overrides the problem's point value.
+type VirtualContestProblemInfo struct {
+	ID    string   `json:"id"`
+	Point *float64 `json:"point"`
+	Order *int     `json:"order"`
+}
+
+// StartTime returns the start time of the contest
+func (v *VirtualContestResponse) StartTime() time.Time {
+	return time.Unix(v.Info.StartEpochSecond, 0)
+}
+
+// Duration returns the length of the contest
+func (v *VirtualContestResponse) Duration() time.Duration {
+	return time.Duration(v.Info.DurationSecond) * time.Second
+}
+
+// ParseVirtualContestID extracts the contest UUID from an ID or an AtCoder Problems URL
+// such as https://kenkoooo.com/atcoder/#/contest/show/<id>
+func ParseVirtualContestID(input string) (string, error) {
+	id := virtualContestIDPattern.FindString(strings.TrimSpace(input))
+	if id == "" {
+		return "", fmt.Errorf("no virtual contest ID in %q", input)
+	}
+	return strings.ToLower(id), nil
+}
+
+// GetVirtualContest retrieves the definition of an AtCoder Problems virtual contest
+func (c *Client) GetVirtualContest(contestID string) (*VirtualContestResponse, error) {
+	endpoint := fmt.Sprintf("/internal-api/contest/get/%s", contestID)
+
+	body, err := c.get(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseVirtualContest(body)
+}
+
+// parseVirtualContest parses a virtual contest and orders its problems as shown on AtCoder Problems
+func parseVirtualContest(body []byte) (*VirtualContestResponse, error) {
+	var contest VirtualContestResponse
+	if err := json.Unmarshal(body, &contest); err != nil {
+		return nil, fmt.Errorf("failed to parse virtual contest: %w", err)
+	}
+	if contest.Info.ID == "" {
+		return nil, fmt.Errorf("failed to parse virtual contest: missing contest info")
+	}
+
+	// Problems without an explicit order keep their position after the ordered ones
+	sort.SliceStable(contest.Problems, func(a, b int) bool {
+		oa, ob := contest.Problems[a].Order, contest.Problems[b].Order
+		if oa == nil || ob == nil {
+			return oa != nil && ob == nil
+		}
+		return *oa < *ob
+	})
+
+	return &contest, nil
+}
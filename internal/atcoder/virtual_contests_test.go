@@ -0,0 +1,85 @@
+package atcoder
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestParseVirtualContest(t *testing.T) {
+	body, err := os.ReadFile("testdata/virtual_contest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	contest, err := parseVirtualContest(body)
+	if err != nil {
+		t.Fatalf("parseVirtualContest: %v", err)
+	}
+
+	if contest.Info.ID != "3f8c2b1e-9a4d-4c1b-8e2f-0d6a7b5c4e21" {
+		t.Errorf("ID = %q", contest.Info.ID)
+	}
+	if contest.Info.Title != "土曜バーチャル #12" {
+		t.Errorf("Title = %q", contest.Info.Title)
+	}
+	if want := time.Unix(1704542400, 0); !contest.StartTime().Equal(want) {
+		t.Errorf("StartTime = %v, want %v", contest.StartTime(), want)
+	}
+	if contest.Duration() != 100*time.Minute {
+		t.Errorf("Duration = %v, want 100m", contest.Duration())
+	}
+	if len(contest.Participants) != 2 {
+		t.Errorf("Participants = %v", contest.Participants)
+	}
+
+	wantIDs := []string{"abc301_d", "abc299_d", "abc300_e", "arc150_b"}
+	if len(contest.Problems) != len(wantIDs) {
+		t.Fatalf("got %d problems, want %d", len(contest.Problems), len(wantIDs))
+	}
+	for i, id := range wantIDs {
+		if contest.Problems[i].ID != id {
+			t.Errorf("problem %d = %q, want %q", i, contest.Problems[i].ID, id)
+		}
+	}
+
+	if contest.Problems[0].Point != nil {
+		t.Errorf("abc301_d point = %v, want nil", *contest.Problems[0].Point)
+	}
+	if p := contest.Problems[3].Point; p == nil || *p != 700 {
+		t.Errorf("arc150_b point = %v, want 700", p)
+	}
+}
+
+func TestParseVirtualContestInvalid(t *testing.T) {
+	if _, err := parseVirtualContest([]byte(`{"problems": []}`)); err == nil {
+		t.Error("expected error for missing contest info")
+	}
+	if _, err := parseVirtualContest([]byte(`not json`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestParseVirtualContestID(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{"3f8c2b1e-9a4d-4c1b-8e2f-0d6a7b5c4e21", "3f8c2b1e-9a4d-4c1b-8e2f-0d6a7b5c4e21", false},
+		{"https://kenkoooo.com/atcoder/#/contest/show/3F8C2B1E-9A4D-4C1B-8E2F-0D6A7B5C4E21", "3f8c2b1e-9a4d-4c1b-8e2f-0d6a7b5c4e21", false},
+		{"https://kenkoooo.com/atcoder/#/contest/show/3f8c2b1e-9a4d-4c1b-8e2f-0d6a7b5c4e21?activeTab=Standings", "3f8c2b1e-9a4d-4c1b-8e2f-0d6a7b5c4e21", false},
+		{"abc300", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVirtualContestID(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseVirtualContestID(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVirtualContestID(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package backfill
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+	"time"
+
+	"coding-winner/internal/atcoder"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// Progress is called after each saved page with the number of submissions fetched so far
+// and the submission time the backfill has reached
+type Progress func(fetched int, reached time.Time)
+
+// running holds the users whose backfill is in progress in this process
+var running sync.Map
+
+// Run pages through a user's whole submission history, oldest first, saving each page and
+// the cursor after it. An interrupted backfill continues from the saved cursor, and a
+// finished one returns immediately. Run reports false if a backfill for the user is
+// already running.
+func Run(db queries.UserDB, client *atcoder.Client, user *models.User, progress Progress) (bool, error) {
+	if _, busy := running.LoadOrStore(user.DiscordID, true); busy {
+		return false, nil
+	}
+	defer running.Delete(user.DiscordID)
+
+	if err := queries.StartSubmissionBackfill(db, user.DiscordID, user.AtCoderUsername); err != nil {
+		return true, err
+	}
+	state, err := queries.GetSubmissionBackfill(db, user.DiscordID)
+	if err != nil {
+		return true, err
+	}
+	if state.CompletedAt.Valid {
+		return true, nil
+	}
+
+	fromSecond, fetched := state.FromSecond, state.Fetched
+	for {
+		page, err := client.GetUserSubmissionsSince(user.AtCoderUsername, time.Unix(fromSecond, 0))
+		if err != nil {
+			return true, fmt.Errorf("failed to fetch submissions from %d: %w", fromSecond, err)
+		}
+
+		submissions := make([]*models.Submission, len(page))
+		for i, sub := range page {
+			submissions[i] = atcoder.ConvertSubmissionToModel(sub, user.DiscordID)
+		}
+		if _, err := queries.CreateSubmissions(db, submissions); err != nil {
+			return true, err
+		}
+
+		fetched += len(page)
+		next := atcoder.NextFromSecond(fromSecond, page, atcoder.SubmissionsPageSize)
+		if err := queries.SaveSubmissionBackfillCursor(db, user.DiscordID, next, fetched); err != nil {
+			return true, err
+		}
+		if progress != nil {
+			progress(fetched, time.Unix(next, 0))
+		}
+
+		if len(page) < atcoder.SubmissionsPageSize {
+			return true, queries.CompleteSubmissionBackfill(db, user.DiscordID, time.Now())
+		}
+		fromSecond = next
+		client.RateLimitDelay()
+	}
+}
+
+// IsPending reports whether a user's history still has to be backfilled
+func IsPending(db queries.UserDB, user *models.User) (bool, error) {
+	state, err := queries.GetSubmissionBackfill(db, user.DiscordID)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return !state.CompletedAt.Valid || state.AtCoderUsername != user.AtCoderUsername, nil
+}
@@ -3,6 +3,7 @@ package bot
 import (
 	"fmt"
 	"log"
+	"strings"
 
 	"github.com/bwmarrin/discordgo"
 	"coding-winner/internal/atcoder"
@@ -66,8 +67,12 @@ func (b *Bot) ready(s *discordgo.Session, event *discordgo.Ready) {
 	}
 }
 
-// interactionCreate handles slash command interactions
+// interactionCreate handles slash command and message component interactions
 func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	if i.Type == discordgo.InteractionMessageComponent {
+		b.componentInteraction(s, i)
+		return
+	}
 	if i.Type != discordgo.InteractionApplicationCommand {
 		return
 	}
@@ -89,6 +94,23 @@ func (b *Bot) interactionCreate(s *discordgo.Session, i *discordgo.InteractionCr
 	}
 }
 
+// componentInteraction handles button interactions.
+// Custom IDs are of the form "<prefix>:<args...>" and are routed by prefix.
+func (b *Bot) componentInteraction(s *discordgo.Session, i *discordgo.InteractionCreate) {
+	customID := i.MessageComponentData().CustomID
+	prefix := strings.SplitN(customID, ":", 2)[0]
+
+	handler, exists := b.getComponentHandlers()[prefix]
+	if !exists {
+		log.Printf("Unknown component: %s", customID)
+		return
+	}
+
+	if err := handler(b, s, i); err != nil {
+		log.Printf("Error handling component %s: %v", customID, err)
+	}
+}
+
 // respondError sends an error response to a slash command
 func (b *Bot) respondError(s *discordgo.Session, i *discordgo.InteractionCreate, message string) {
 	s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
@@ -15,13 +15,43 @@ type CommandHandler func(b *Bot, s *discordgo.Session, i *discordgo.InteractionC
 func (b *Bot) getCommandHandlers() map[string]CommandHandler {
 	return map[string]CommandHandler{
 		"register":          b.wrapHandler(handlers.HandleRegister(b.DB, b.AtCoderClient)),
+		"verify":            b.wrapHandler(handlers.HandleVerify(b.DB, b.AtCoderClient, b.AtCoderClient)),
+		"sync-status":       b.wrapHandler(handlers.HandleSyncStatus(b.DB)),
 		"contest-notify":    b.wrapHandler(handlers.HandleContestNotify(b.DB)),
 		"weekly-report":     b.wrapHandler(handlers.HandleWeeklyReport(b.DB)),
 		"daily-problem":     b.wrapHandler(handlers.HandleDailyProblem(b.DB)),
 		"virtual-create":    b.wrapHandler(handlers.HandleVirtualCreate(b.DB)),
+		"virtual-auto":      b.wrapHandler(handlers.HandleVirtualAuto(b.DB)),
+		"virtual-replay":    b.wrapHandler(handlers.HandleVirtualReplay(b.DB)),
+		"virtual-import":    b.wrapHandler(handlers.HandleVirtualImport(b.DB, b.AtCoderClient)),
+		"virtual-poll":      b.wrapHandler(handlers.HandleVirtualPoll(b.DB)),
 		"virtual-start":     b.wrapHandler(handlers.HandleVirtualStart(b.DB)),
 		"virtual-standings": b.wrapHandler(handlers.HandleVirtualStandings(b.DB)),
+		"virtual-cancel":    b.wrapHandler(handlers.HandleVirtualCancel(b.DB)),
+		"virtual-edit":      b.wrapHandler(handlers.HandleVirtualEdit(b.DB)),
+		"virtual-list":      b.wrapHandler(handlers.HandleVirtualList(b.DB)),
+		"virtual-export":    b.wrapHandler(handlers.HandleVirtualExport(b.DB)),
+		"virtual-team":      b.wrapHandler(handlers.HandleVirtualTeam(b.DB)),
+		"virtual-recurring": b.wrapHandler(handlers.HandleVirtualRecurring(b.DB)),
+		"vrating":           b.wrapHandler(handlers.HandleVirtualRating(b.DB)),
 		"mystats":           b.wrapHandler(handlers.HandleMyStats(b.DB)),
+		"leaderboard":       b.wrapHandler(handlers.HandleLeaderboard(b.DB)),
+		"compare":           b.wrapHandler(handlers.HandleCompare(b.DB)),
+		"recommend":         b.wrapHandler(handlers.HandleRecommend(b.DB)),
+		"goal":              b.wrapHandler(handlers.HandleGoal(b.DB, b.AtCoderClient)),
+		"badges":            b.wrapHandler(handlers.HandleBadges(b.DB)),
+		"badge-notify":      b.wrapHandler(handlers.HandleBadgeNotify(b.DB)),
+	}
+}
+
+// getComponentHandlers returns message component handlers keyed by custom ID prefix
+func (b *Bot) getComponentHandlers() map[string]CommandHandler {
+	return map[string]CommandHandler{
+		"leaderboard":   b.wrapHandler(handlers.HandleLeaderboardPage(b.DB)),
+		"virtual-list":  b.wrapHandler(handlers.HandleVirtualListPage(b.DB)),
+		"virtual-join":  b.wrapHandler(handlers.HandleVirtualJoin(b.DB)),
+		"virtual-leave": b.wrapHandler(handlers.HandleVirtualLeave(b.DB)),
+		"virtual-poll":  b.wrapHandler(handlers.HandleVirtualPollVote(b.DB)),
 	}
 }
 
@@ -46,6 +76,28 @@ var commands = []*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	{
+		Name:        "verify",
+		Description: "AtCoderのプロフィールの所属欄で本人確認",
+	},
+	{
+		Name:        "sync-status",
+		Description: "提出データの同期状況を表示",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "表示するユーザー（デフォルト: 自分）",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionBoolean,
+				Name:        "failing",
+				Description: "同期に失敗しているユーザーを一覧表示（管理者のみ）",
+				Required:    false,
+			},
+		},
+	},
 	{
 		Name:        "contest-notify",
 		Description: "コンテスト通知を設定",
@@ -119,9 +171,152 @@ var commands = []*discordgo.ApplicationCommand{
 			{
 				Type:        discordgo.ApplicationCommandOptionString,
 				Name:        "problems",
-				Description: "問題ID（カンマ区切り、例: abc001_a,abc002_b）",
+				Description: "問題ID・URL・「コンテストID 記号」のカンマ区切り（例: abc300_a, abc301 C-F）",
 				Required:    true,
 			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start",
+				Description: "開始時刻（JST、例: 2024-01-06 21:00）。指定すると自動で開始",
+				Required:    false,
+			},
+			penaltyRuleOption,
+			presolvedPolicyOption,
+		},
+	},
+	{
+		Name:        "virtual-auto",
+		Description: "未解決の問題から難易度順のバーチャルコンテストを自動生成",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "count",
+				Description: "問題数（1〜26）",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "range",
+				Description: "難易度の範囲（例: 400-1600）",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "title",
+				Description: "コンテストのタイトル",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "duration",
+				Description: "コンテスト時間（分、デフォルト: 100）",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start",
+				Description: "開始時刻（JST、例: 2024-01-06 21:00）。指定すると自動で開始",
+				Required:    false,
+			},
+			penaltyRuleOption,
+			presolvedPolicyOption,
+		},
+	},
+	{
+		Name:        "virtual-replay",
+		Description: "過去のAtCoderコンテストを元の配点・時間でバーチャルとして再現",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "contest",
+				Description: "コンテストIDまたはURL（例: abc300）",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start",
+				Description: "開始時刻（JST、例: 2024-01-06 21:00）。指定すると自動で開始",
+				Required:    false,
+			},
+			penaltyRuleOption,
+			presolvedPolicyOption,
+		},
+	},
+	{
+		Name:        "virtual-import",
+		Description: "AtCoder Problemsのバーチャルコンテストを取り込む",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "contest",
+				Description: "AtCoder ProblemsのコンテストIDまたはURL",
+				Required:    true,
+			},
+			penaltyRuleOption,
+		},
+	},
+	{
+		Name:        "virtual-poll",
+		Description: "次のバーチャルコンテストの問題セットやリプレイするコンテストを投票で決める",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "mode",
+				Description: "候補の種類",
+				Required:    true,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "問題セット（未解決の問題から自動生成）", Value: "auto"},
+					{Name: "過去コンテストのリプレイ", Value: "replay"},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "choices",
+				Description: "候補数（2〜5、デフォルト: 3）",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "deadline",
+				Description: "投票の締め切り（分後、デフォルト: 60）",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "count",
+				Description: "問題セットの問題数（1〜10、デフォルト: 5）",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "range",
+				Description: "問題セットの難易度の範囲（デフォルト: 400-1600）",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "series",
+				Description: "リプレイするコンテストの種類（デフォルト: ABC）",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "ABC", Value: "abc"},
+					{Name: "ARC", Value: "arc"},
+					{Name: "AGC", Value: "agc"},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "duration",
+				Description: "問題セットのコンテスト時間（分、デフォルト: 100）",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start",
+				Description: "開始時刻（JST、締め切りより後）。指定すると自動で開始",
+				Required:    false,
+			},
+			penaltyRuleOption,
 		},
 	},
 	{
@@ -148,10 +343,439 @@ var commands = []*discordgo.ApplicationCommand{
 			},
 		},
 	},
+	{
+		Name:        "virtual-cancel",
+		Description: "バーチャルコンテストを中止（作成者・管理者のみ）",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "contest-id",
+				Description: "コンテストID",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "virtual-edit",
+		Description: "開始前のバーチャルコンテストを編集（作成者・管理者のみ）",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "contest-id",
+				Description: "コンテストID",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "title",
+				Description: "コンテストのタイトル",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "duration",
+				Description: "コンテスト時間（分）",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "problems",
+				Description: "問題ID・URL・「コンテストID 記号」のカンマ区切り（例: abc300_a, abc301 C-F）",
+				Required:    false,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "start",
+				Description: "開始時刻（JST、例: 2024-01-06 21:00）",
+				Required:    false,
+			},
+			penaltyRuleOption,
+			presolvedPolicyOption,
+		},
+	},
+	{
+		Name:        "virtual-list",
+		Description: "サーバーのバーチャルコンテスト一覧を表示",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "filter",
+				Description: "表示するコンテスト（デフォルト: すべて）",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "開始前", Value: "upcoming"},
+					{Name: "開催中", Value: "running"},
+					{Name: "過去", Value: "past"},
+					{Name: "すべて", Value: "all"},
+				},
+			},
+		},
+	},
+	{
+		Name:        "virtual-export",
+		Description: "バーチャルコンテストの順位表と提出履歴をファイルで出力",
+		Options: []*discordgo.ApplicationCommandOption{
+			virtualContestIDOption,
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "format",
+				Description: "出力形式（デフォルト: csv）",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "CSV", Value: "csv"},
+					{Name: "JSON", Value: "json"},
+				},
+			},
+		},
+	},
+	{
+		Name:        "virtual-team",
+		Description: "チーム戦バーチャルコンテストのチームを管理",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "create",
+				Description: "チームを作成して参加",
+				Options:     []*discordgo.ApplicationCommandOption{virtualContestIDOption, teamNameOption},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "join",
+				Description: "チームに参加",
+				Options:     []*discordgo.ApplicationCommandOption{virtualContestIDOption, teamNameOption},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "add",
+				Description: "メンバーをチームに追加（作成者・管理者のみ）",
+				Options: []*discordgo.ApplicationCommandOption{
+					virtualContestIDOption,
+					teamNameOption,
+					{
+						Type:        discordgo.ApplicationCommandOptionUser,
+						Name:        "user",
+						Description: "追加するユーザー",
+						Required:    true,
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "leave",
+				Description: "チームから抜ける",
+				Options:     []*discordgo.ApplicationCommandOption{virtualContestIDOption},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "チーム一覧を表示",
+				Options:     []*discordgo.ApplicationCommandOption{virtualContestIDOption},
+			},
+		},
+	},
+	{
+		Name:        "virtual-recurring",
+		Description: "定期開催のバーチャルコンテストを管理",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "create",
+				Description: "定期バーチャルを登録（このチャンネルでお知らせ・管理者のみ）",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "title",
+						Description: "コンテストのタイトル",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "schedule",
+						Description: "開始スケジュール（cron形式・JST、例: 0 21 * * 6 = 毎週土曜21時）",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "duration",
+						Description: "コンテスト時間（分）",
+						Required:    true,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "problems",
+						Description: "固定の問題リスト（/virtual-create と同じ形式）",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "count",
+						Description: "自動選択する問題数（range と併用）",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "range",
+						Description: "自動選択する難易度の範囲（例: 400-1600）",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "announce",
+						Description: "何分前にお知らせするか（デフォルト: 60）",
+						Required:    false,
+					},
+					penaltyRuleOption,
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "定期バーチャルの一覧を表示",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "delete",
+				Description: "定期バーチャルを削除（登録者・管理者のみ）",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "id",
+						Description: "定期バーチャルのID",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:        "vrating",
+		Description: "バーチャルコンテストによるサーバー内レーティングと履歴を表示",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "表示するユーザー（デフォルト: 自分）",
+				Required:    false,
+			},
+		},
+	},
 	{
 		Name:        "mystats",
 		Description: "自分の統計情報を表示",
 	},
+	{
+		Name:        "leaderboard",
+		Description: "サーバー内のランキングを表示",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "metric",
+				Description: "ランキングの指標（デフォルト: ac）",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "AC数", Value: "ac"},
+					{Name: "新規AC数", Value: "new-ac"},
+					{Name: "難易度加重スコア", Value: "weighted"},
+					{Name: "最長連続AC日数", Value: "streak"},
+					{Name: "最高難易度", Value: "max-difficulty"},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "period",
+				Description: "集計期間（デフォルト: week）",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "今週", Value: "week"},
+					{Name: "今月", Value: "month"},
+					{Name: "今年", Value: "year"},
+					{Name: "全期間", Value: "all"},
+				},
+			},
+		},
+	},
+	{
+		Name:        "compare",
+		Description: "2人のメンバーの精進状況を比較",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user_a",
+				Description: "比較するユーザー",
+				Required:    true,
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user_b",
+				Description: "比較するユーザー",
+				Required:    true,
+			},
+		},
+	},
+	{
+		Name:        "recommend",
+		Description: "実力に合ったおすすめ問題を表示",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionString,
+				Name:        "mode",
+				Description: "難しさ（デフォルト: moderate）",
+				Required:    false,
+				Choices: []*discordgo.ApplicationCommandOptionChoice{
+					{Name: "Easy", Value: "easy"},
+					{Name: "Moderate", Value: "moderate"},
+					{Name: "Hard", Value: "hard"},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionInteger,
+				Name:        "count",
+				Description: "問題数（デフォルト: 5）",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "goal",
+		Description: "個人目標を管理",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "set",
+				Description: "目標を設定",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "kind",
+						Description: "目標の種類",
+						Required:    true,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "新規AC数", Value: "new-ac"},
+							{Name: "指定色の新規AC数", Value: "color-ac"},
+							{Name: "レート到達", Value: "rating"},
+						},
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "count",
+						Description: "問題数（new-ac / color-ac）",
+						Required:    false,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "color",
+						Description: "難易度またはレートの色（color-ac / rating）",
+						Required:    false,
+						Choices:     colorChoices,
+					},
+					{
+						Type:        discordgo.ApplicationCommandOptionString,
+						Name:        "period",
+						Description: "期間（デフォルト: week）",
+						Required:    false,
+						Choices: []*discordgo.ApplicationCommandOptionChoice{
+							{Name: "今週", Value: "week"},
+							{Name: "今月", Value: "month"},
+						},
+					},
+				},
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "list",
+				Description: "目標と進捗を表示",
+			},
+			{
+				Type:        discordgo.ApplicationCommandOptionSubCommand,
+				Name:        "delete",
+				Description: "目標を削除",
+				Options: []*discordgo.ApplicationCommandOption{
+					{
+						Type:        discordgo.ApplicationCommandOptionInteger,
+						Name:        "id",
+						Description: "目標ID",
+						Required:    true,
+					},
+				},
+			},
+		},
+	},
+	{
+		Name:        "badges",
+		Description: "獲得したバッジを表示",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionUser,
+				Name:        "user",
+				Description: "表示するユーザー（デフォルト: 自分）",
+				Required:    false,
+			},
+		},
+	},
+	{
+		Name:        "badge-notify",
+		Description: "バッジ獲得のお知らせを設定",
+		Options: []*discordgo.ApplicationCommandOption{
+			{
+				Type:        discordgo.ApplicationCommandOptionChannel,
+				Name:        "channel",
+				Description: "お知らせを送信するチャンネル",
+				Required:    true,
+			},
+		},
+	},
+}
+
+// penaltyRuleOption selects the standings rule of a virtual contest
+var penaltyRuleOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "rule",
+	Description: "順位のルール（デフォルト: atcoder）",
+	Required:    false,
+	Choices: []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "AtCoder（得点→最終AC時間+ペナルティ5分）", Value: "atcoder"},
+		{Name: "ICPC（正解数→合計時間+ペナルティ20分）", Value: "icpc"},
+	},
+}
+
+// presolvedPolicyOption selects how problems solved before the start are treated
+var presolvedPolicyOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "presolved",
+	Description: "開始前にAC済みの問題の扱い（デフォルト: warn）",
+	Required:    false,
+	Choices: []*discordgo.ApplicationCommandOptionChoice{
+		{Name: "警告のみ（順位表に印を付ける）", Value: "warn"},
+		{Name: "その参加者の得点から除外", Value: "exclude"},
+	},
+}
+
+// virtualContestIDOption selects a virtual contest in subcommands
+var virtualContestIDOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionInteger,
+	Name:        "contest-id",
+	Description: "コンテストID",
+	Required:    true,
+}
+
+// teamNameOption names a team of a virtual contest
+var teamNameOption = &discordgo.ApplicationCommandOption{
+	Type:        discordgo.ApplicationCommandOptionString,
+	Name:        "name",
+	Description: "チーム名",
+	Required:    true,
+}
+
+// colorChoices lists the AtCoder colors as command choices
+var colorChoices = []*discordgo.ApplicationCommandOptionChoice{
+	{Name: "灰色", Value: "gray"},
+	{Name: "茶色", Value: "brown"},
+	{Name: "緑色", Value: "green"},
+	{Name: "水色", Value: "cyan"},
+	{Name: "青色", Value: "blue"},
+	{Name: "黄色", Value: "yellow"},
+	{Name: "橙色", Value: "orange"},
+	{Name: "赤色", Value: "red"},
 }
 
 // registerCommands registers all slash commands with Discord
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/achievements"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// HandleBadges handles the /badges command
+func HandleBadges(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		discordID := i.Member.User.ID
+		options := i.ApplicationCommandData().Options
+		if len(options) > 0 {
+			discordID = options[0].UserValue(s).ID
+		}
+
+		user, err := queries.GetUser(db, discordID)
+		if err != nil {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ <@%s> はユーザー登録されていません。", discordID))
+		}
+
+		badges, err := queries.GetUserBadges(db, discordID)
+		if err != nil {
+			return err
+		}
+		unlocked := make(map[string]*models.UserBadge, len(badges))
+		for _, b := range badges {
+			unlocked[b.BadgeKey] = b
+		}
+
+		var sb strings.Builder
+		for _, badge := range achievements.Badges {
+			if ub, ok := unlocked[badge.Key]; ok {
+				sb.WriteString(fmt.Sprintf("%s **%s** - %s（%s）\n",
+					badge.Emoji, badge.Name, badge.Description, ub.UnlockedAt.Format("2006/01/02")))
+			} else {
+				sb.WriteString(fmt.Sprintf("🔒 %s - %s\n", badge.Name, badge.Description))
+			}
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("🏅 %s のバッジ", user.AtCoderUsername),
+			Description: sb.String(),
+			Color:       0xe67e22,
+			Footer: &discordgo.MessageEmbedFooter{
+				Text: fmt.Sprintf("%d / %d 獲得", len(unlocked), len(achievements.Badges)),
+			},
+			Timestamp: time.Now().Format(time.RFC3339),
+		}
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{embed},
+			},
+		})
+	}
+}
+
+// HandleBadgeNotify handles the /badge-notify command
+func HandleBadgeNotify(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		options := i.ApplicationCommandData().Options
+		channelID := options[0].ChannelValue(s).ID
+
+		config := &models.BadgeConfig{
+			ServerID:  i.GuildID,
+			ChannelID: channelID,
+			Enabled:   true,
+		}
+
+		if err := queries.SaveBadgeConfig(db, config); err != nil {
+			return err
+		}
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("✅ バッジ獲得のお知らせを <#%s> に設定しました。", channelID),
+			},
+		})
+	}
+}
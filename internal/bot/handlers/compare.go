@@ -0,0 +1,187 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// colorOrder lists difficulty colors from hardest to easiest, followed by unknown
+var colorOrder = []string{"赤色", "橙色", "黄色", "青色", "水色", "緑色", "茶色", "灰色", "不明"}
+
+// compareContestLimit is the number of common contests shown
+const compareContestLimit = 10
+
+// HandleCompare handles the /compare command
+func HandleCompare(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		options := i.ApplicationCommandData().Options
+		userA, err := queries.GetUser(db, options[0].UserValue(s).ID)
+		if err != nil {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ <@%s> はユーザー登録されていません。", options[0].UserValue(s).ID))
+		}
+		userB, err := queries.GetUser(db, options[1].UserValue(s).ID)
+		if err != nil {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ <@%s> はユーザー登録されていません。", options[1].UserValue(s).ID))
+		}
+		if userA.DiscordID == userB.DiscordID {
+			return respondEphemeral(s, i, "❌ 異なる2人のユーザーを指定してください。")
+		}
+
+		onlyA, err := queries.GetSolvedNotSolvedBy(db, userA.DiscordID, userB.DiscordID)
+		if err != nil {
+			return err
+		}
+		onlyB, err := queries.GetSolvedNotSolvedBy(db, userB.DiscordID, userA.DiscordID)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		weekStart := periodStart("week", now)
+		weeklyA, err := queries.GetACCountByDifficulty(db, userA.DiscordID, weekStart, now)
+		if err != nil {
+			return err
+		}
+		weeklyB, err := queries.GetACCountByDifficulty(db, userB.DiscordID, weekStart, now)
+		if err != nil {
+			return err
+		}
+
+		contests, err := queries.GetCommonContestResults(db, userA.DiscordID, userB.DiscordID)
+		if err != nil {
+			return err
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title:     fmt.Sprintf("⚔️ %s vs %s", userA.AtCoderUsername, userB.AtCoderUsername),
+			Color:     0xe74c3c,
+			Timestamp: now.Format(time.RFC3339),
+			Fields: []*discordgo.MessageEmbedField{
+				{
+					Name: fmt.Sprintf("今週のAC数（%s 〜）", weekStart.Format("01/02")),
+					Value: fmt.Sprintf("%s: %d\n%s: %d",
+						userA.AtCoderUsername, sumCounts(weeklyA), userB.AtCoderUsername, sumCounts(weeklyB)),
+					Inline: false,
+				},
+				{
+					Name:   fmt.Sprintf("%s だけが解いた問題（%d問）", userA.AtCoderUsername, len(onlyA)),
+					Value:  formatProblemsByColor(onlyA),
+					Inline: true,
+				},
+				{
+					Name:   fmt.Sprintf("%s だけが解いた問題（%d問）", userB.AtCoderUsername, len(onlyB)),
+					Value:  formatProblemsByColor(onlyB),
+					Inline: true,
+				},
+				{
+					Name:   "共通参加コンテスト",
+					Value:  formatContestComparisons(contests),
+					Inline: false,
+				},
+			},
+		}
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{embed},
+			},
+		})
+	}
+}
+
+// formatProblemsByColor summarizes problems as per-color counts with a few examples
+func formatProblemsByColor(problems []*models.Problem) string {
+	if len(problems) == 0 {
+		return "なし"
+	}
+
+	byColor := make(map[string][]string)
+	for _, p := range problems {
+		color := "不明"
+		if p.Difficulty.Valid {
+			color = queries.DifficultyToColor(int(p.Difficulty.Int64))
+		}
+		byColor[color] = append(byColor[color], p.ProblemID)
+	}
+
+	var sb strings.Builder
+	for _, color := range colorOrder {
+		ids := byColor[color]
+		if len(ids) == 0 {
+			continue
+		}
+		examples := ids
+		if len(examples) > 3 {
+			examples = examples[:3]
+		}
+		sb.WriteString(fmt.Sprintf("%s: %d（%s", color, len(ids), strings.Join(examples, ", ")))
+		if len(ids) > len(examples) {
+			sb.WriteString(", …")
+		}
+		sb.WriteString("）\n")
+	}
+	return sb.String()
+}
+
+// formatContestComparisons summarizes head-to-head contest results
+func formatContestComparisons(contests []models.ContestComparison) string {
+	if len(contests) == 0 {
+		return "なし"
+	}
+
+	winsA, winsB, draws := 0, 0, 0
+	for _, c := range contests {
+		switch {
+		case c.ScoreA > c.ScoreB:
+			winsA++
+		case c.ScoreA < c.ScoreB:
+			winsB++
+		default:
+			draws++
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%d勝 %d敗 %d分（%dコンテスト）\n", winsA, winsB, draws, len(contests)))
+	for idx, c := range contests {
+		if idx >= compareContestLimit {
+			break
+		}
+		mark := "➖"
+		if c.ScoreA > c.ScoreB {
+			mark = "◀"
+		} else if c.ScoreA < c.ScoreB {
+			mark = "▶"
+		}
+		sb.WriteString(fmt.Sprintf("`%s` %.0f点(%d完) %s %.0f点(%d完)\n",
+			c.ContestID, c.ScoreA, c.SolvedA, mark, c.ScoreB, c.SolvedB))
+	}
+	return sb.String()
+}
+
+// sumCounts sums the values of a count map
+func sumCounts(counts map[string]int) int {
+	total := 0
+	for _, count := range counts {
+		total += count
+	}
+	return total
+}
+
+// respondEphemeral sends an ephemeral message response
+func respondEphemeral(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: content,
+			Flags:   discordgo.MessageFlagsEphemeral,
+		},
+	})
+}
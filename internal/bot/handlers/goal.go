@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/atcoder"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/goals"
+	"coding-winner/internal/models"
+)
+
+// HandleGoal handles the /goal command and its subcommands
+func HandleGoal(db *database.DB, atcoderClient *atcoder.Client) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		user, err := queries.GetUser(db, i.Member.User.ID)
+		if err != nil {
+			return respondEphemeral(s, i, "❌ ユーザー登録されていません。`/register` コマンドで登録してください。")
+		}
+
+		sub := i.ApplicationCommandData().Options[0]
+		switch sub.Name {
+		case "set":
+			return handleGoalSet(db, s, i, user, sub.Options)
+		case "list":
+			return handleGoalList(db, atcoderClient, s, i, user)
+		case "delete":
+			goalID := int(sub.Options[0].IntValue())
+			if err := queries.DeleteGoal(db, goalID, user.DiscordID); err != nil {
+				return respondEphemeral(s, i, "❌ 指定された目標が見つかりませんでした。")
+			}
+			return respondEphemeral(s, i, fmt.Sprintf("✅ 目標 #%d を削除しました。", goalID))
+		default:
+			return fmt.Errorf("unknown goal subcommand: %s", sub.Name)
+		}
+	}
+}
+
+// handleGoalSet handles /goal set
+func handleGoalSet(db *database.DB, s *discordgo.Session, i *discordgo.InteractionCreate, user *models.User, options []*discordgo.ApplicationCommandInteractionDataOption) error {
+	var kind, colorKey string
+	count := 0
+	period := "week"
+	for _, opt := range options {
+		switch opt.Name {
+		case "kind":
+			kind = opt.StringValue()
+		case "count":
+			count = int(opt.IntValue())
+		case "color":
+			colorKey = opt.StringValue()
+		case "period":
+			period = opt.StringValue()
+		}
+	}
+
+	goal := &models.Goal{
+		UserID:    user.DiscordID,
+		ServerID:  i.GuildID,
+		ChannelID: i.ChannelID,
+		Kind:      kind,
+		Target:    count,
+	}
+
+	color, hasColor := goals.FindColor(colorKey)
+	if hasColor {
+		goal.Color = sql.NullString{String: color.Key, Valid: true}
+	}
+
+	switch kind {
+	case goals.KindNewAC, goals.KindColorAC:
+		if count <= 0 {
+			return respondEphemeral(s, i, "❌ `count` に1以上の問題数を指定してください。")
+		}
+		if kind == goals.KindColorAC && !hasColor {
+			return respondEphemeral(s, i, "❌ `color` を指定してください。")
+		}
+
+		start := periodStart(period, time.Now())
+		end := start.AddDate(0, 0, 7)
+		if period == "month" {
+			end = start.AddDate(0, 1, 0)
+		}
+		goal.PeriodStart = sql.NullTime{Time: start, Valid: true}
+		goal.PeriodEnd = sql.NullTime{Time: end, Valid: true}
+	case goals.KindRating:
+		if !hasColor || color.Min == 0 {
+			return respondEphemeral(s, i, "❌ `color` に目標の色（茶色以上）を指定してください。")
+		}
+		goal.Target = color.Min
+	default:
+		return respondEphemeral(s, i, "❌ 目標の種類が不正です。")
+	}
+
+	goalID, err := queries.CreateGoal(db, goal)
+	if err != nil {
+		return err
+	}
+
+	return respondEphemeral(s, i, fmt.Sprintf("✅ 目標 #%d を設定しました: %s", goalID, goals.Describe(goal)))
+}
+
+// handleGoalList handles /goal list
+func handleGoalList(db *database.DB, atcoderClient *atcoder.Client, s *discordgo.Session, i *discordgo.InteractionCreate, user *models.User) error {
+	// Rating goals require an AtCoder request, so defer the response
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Flags: discordgo.MessageFlagsEphemeral,
+		},
+	}); err != nil {
+		return err
+	}
+
+	userGoals, err := queries.GetUserGoals(db, user.DiscordID, i.GuildID)
+	if err != nil {
+		updateResponse(s, i, "❌ 目標の取得に失敗しました。")
+		return err
+	}
+
+	if len(userGoals) == 0 {
+		return updateResponse(s, i, "目標が設定されていません。`/goal set` で設定しましょう！")
+	}
+
+	now := time.Now()
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("🎯 **%s の目標**\n\n", user.AtCoderUsername))
+	for _, goal := range userGoals {
+		status := ""
+		switch {
+		case goal.CompletedAt.Valid:
+			status = fmt.Sprintf("✅ 達成（%s）", goal.CompletedAt.Time.Format("01/02"))
+		case goal.PeriodEnd.Valid && !goal.PeriodEnd.Time.After(now):
+			status = "⌛ 期限切れ"
+		default:
+			progress, err := goals.Progress(db, atcoderClient, user, goal)
+			if err != nil {
+				log.Printf("Error computing progress of goal %d: %v", goal.ID, err)
+				status = "🔄 進捗を取得できませんでした"
+			} else {
+				status = fmt.Sprintf("🔄 %d / %d", progress, goal.Target)
+			}
+		}
+		sb.WriteString(fmt.Sprintf("#%d %s - %s\n", goal.ID, goals.Describe(goal), status))
+	}
+
+	return updateResponse(s, i, sb.String())
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// leaderboardPageSize is the number of entries shown per page
+const leaderboardPageSize = 10
+
+var metricLabels = map[string]string{
+	queries.MetricAC:            "AC数",
+	queries.MetricNewAC:         "新規AC数",
+	queries.MetricWeighted:      "難易度加重スコア",
+	queries.MetricStreak:        "最長連続AC日数",
+	queries.MetricMaxDifficulty: "最高難易度",
+}
+
+var metricUnits = map[string]string{
+	queries.MetricAC:            "AC",
+	queries.MetricNewAC:         "AC",
+	queries.MetricWeighted:      "pt",
+	queries.MetricStreak:        "日",
+	queries.MetricMaxDifficulty: "",
+}
+
+var periodLabels = map[string]string{
+	"week":  "今週",
+	"month": "今月",
+	"year":  "今年",
+	"all":   "全期間",
+}
+
+// HandleLeaderboard handles the /leaderboard command
+func HandleLeaderboard(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		metric := queries.MetricAC
+		period := "week"
+		for _, opt := range i.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "metric":
+				metric = opt.StringValue()
+			case "period":
+				period = opt.StringValue()
+			}
+		}
+
+		// Guild member lookups may take a while, so defer the response
+		if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		}); err != nil {
+			return err
+		}
+
+		return editLeaderboard(db, s, i, metric, period, 0)
+	}
+}
+
+// HandleLeaderboardPage handles the leaderboard pagination buttons
+func HandleLeaderboardPage(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		// Custom ID format: leaderboard:<metric>:<period>:<page>
+		args, page, err := handlePageButton(s, i, 2)
+		if err != nil {
+			return err
+		}
+		return editLeaderboard(db, s, i, args[0], args[1], page)
+	}
+}
+
+// editLeaderboard builds the requested leaderboard page and edits the deferred response
+func editLeaderboard(db *database.DB, s *discordgo.Session, i *discordgo.InteractionCreate, metric, period string, page int) error {
+	now := time.Now()
+	entries, err := queries.GetLeaderboard(db, metric, periodStart(period, now), now)
+	if err != nil {
+		updateResponse(s, i, "❌ ランキングの取得に失敗しました。")
+		return err
+	}
+	entries = filterGuildMembers(s, i.GuildID, entries)
+
+	page, totalPages := clampPage(page, len(entries), leaderboardPageSize)
+	embed := buildLeaderboardEmbed(entries, metric, period, page, totalPages)
+	return editPage(s, i, embed, fmt.Sprintf("leaderboard:%s:%s", metric, period), page, totalPages)
+}
+
+// buildLeaderboardEmbed builds an embed for one leaderboard page
+func buildLeaderboardEmbed(entries []models.LeaderboardEntry, metric, period string, page, totalPages int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("🏆 ランキング - %s（%s）", metricLabels[metric], periodLabels[period]),
+		Color:     0xf1c40f,
+		Footer:    &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("%d / %d ページ", page+1, totalPages)},
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	if len(entries) == 0 {
+		embed.Description = "該当するユーザーがいません。"
+		return embed
+	}
+
+	var sb strings.Builder
+	start := page * leaderboardPageSize
+	end := start + leaderboardPageSize
+	if end > len(entries) {
+		end = len(entries)
+	}
+	for _, entry := range entries[start:end] {
+		rankEmoji := "🏅"
+		switch entry.Rank {
+		case 1:
+			rankEmoji = "🥇"
+		case 2:
+			rankEmoji = "🥈"
+		case 3:
+			rankEmoji = "🥉"
+		}
+		sb.WriteString(fmt.Sprintf("%s **%d位** %s: %d%s\n",
+			rankEmoji, entry.Rank, entry.AtCoderUsername, entry.Value, metricUnits[metric]))
+	}
+	embed.Description = sb.String()
+
+	return embed
+}
+
+// periodStart returns the start of the given period relative to now.
+// Weeks start on Monday; "all" returns the zero time.
+func periodStart(period string, now time.Time) time.Time {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch period {
+	case "week":
+		offset := (int(now.Weekday()) + 6) % 7
+		return today.AddDate(0, 0, -offset)
+	case "month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	case "year":
+		return time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// filterGuildMembers keeps only entries whose Discord user is a member of the guild
+// and re-assigns ranks accordingly
+func filterGuildMembers(s *discordgo.Session, guildID string, entries []models.LeaderboardEntry) []models.LeaderboardEntry {
+	filtered := make([]models.LeaderboardEntry, 0, len(entries))
+	for _, entry := range entries {
+		if !isGuildMember(s, guildID, entry.UserID) {
+			continue
+		}
+		entry.Rank = len(filtered) + 1
+		filtered = append(filtered, entry)
+	}
+	return filtered
+}
+
+// isGuildMember checks whether a Discord user belongs to the guild
+func isGuildMember(s *discordgo.Session, guildID, userID string) bool {
+	if _, err := s.State.Member(guildID, userID); err == nil {
+		return true
+	}
+	_, err := s.GuildMember(guildID, userID)
+	return err == nil
+}
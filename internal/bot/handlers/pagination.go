@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+// handlePageButton parses a pagination button's custom ID of the form <name>:<args...>:<page>
+// and acknowledges the click with a deferred update. It returns the args and the page.
+func handlePageButton(s *discordgo.Session, i *discordgo.InteractionCreate, argCount int) ([]string, int, error) {
+	customID := i.MessageComponentData().CustomID
+	parts := strings.Split(customID, ":")
+	if len(parts) != argCount+2 {
+		return nil, 0, fmt.Errorf("invalid pagination custom ID: %s", customID)
+	}
+	page, err := strconv.Atoi(parts[len(parts)-1])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseDeferredMessageUpdate,
+	}); err != nil {
+		return nil, 0, err
+	}
+	return parts[1 : len(parts)-1], page, nil
+}
+
+// clampPage keeps page within the pages needed for count items, and returns it with the
+// number of pages. An empty list still has one page.
+func clampPage(page, count, pageSize int) (int, int) {
+	totalPages := (count + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+	if page < 0 {
+		page = 0
+	}
+	if page >= totalPages {
+		page = totalPages - 1
+	}
+	return page, totalPages
+}
+
+// editPage shows one page in the deferred response, with previous/next buttons whose
+// custom IDs are customIDPrefix followed by the target page
+func editPage(s *discordgo.Session, i *discordgo.InteractionCreate, embed *discordgo.MessageEmbed, customIDPrefix string, page, totalPages int) error {
+	components := []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "◀ 前へ",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%d", customIDPrefix, page-1),
+					Disabled: page == 0,
+				},
+				discordgo.Button{
+					Label:    "次へ ▶",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("%s:%d", customIDPrefix, page+1),
+					Disabled: page >= totalPages-1,
+				},
+			},
+		},
+	}
+
+	empty := ""
+	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+		Content:    &empty,
+		Embeds:     &[]*discordgo.MessageEmbed{embed},
+		Components: &components,
+	})
+	return err
+}
@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/atcoder"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// recommendTargets is the solve probability aimed at by each mode
+var recommendTargets = map[string]float64{
+	"easy":     0.8,
+	"moderate": 0.5,
+	"hard":     0.2,
+}
+
+var recommendModeLabels = map[string]string{
+	"easy":     "Easy",
+	"moderate": "Moderate",
+	"hard":     "Hard",
+}
+
+// HandleRecommend handles the /recommend command
+func HandleRecommend(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		discordID := i.Member.User.ID
+
+		user, err := queries.GetUser(db, discordID)
+		if err != nil {
+			return respondEphemeral(s, i, "❌ ユーザー登録されていません。`/register` コマンドで登録してください。")
+		}
+
+		mode := "moderate"
+		count := 5
+		for _, opt := range i.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "mode":
+				mode = opt.StringValue()
+			case "count":
+				count = int(opt.IntValue())
+			}
+		}
+		if count < 1 || count > 20 {
+			return respondEphemeral(s, i, "❌ 問題数は1〜20で指定してください。")
+		}
+
+		attempts, err := queries.GetUserProblemAttempts(db, discordID)
+		if err != nil {
+			return err
+		}
+		if len(attempts) == 0 {
+			return respondEphemeral(s, i, "❌ 提出履歴がないため、おすすめ問題を計算できません。")
+		}
+
+		candidates, err := queries.GetUnsolvedModeledProblems(db, discordID)
+		if err != nil {
+			return err
+		}
+
+		rating := estimateUserRating(attempts)
+		recommended := recommendProblems(candidates, rating, recommendTargets[mode], count)
+
+		embed := &discordgo.MessageEmbed{
+			Title:       fmt.Sprintf("🎯 %s へのおすすめ問題（%s）", user.AtCoderUsername, recommendModeLabels[mode]),
+			Description: fmt.Sprintf("推定実力: %.0f", rating),
+			Color:       0x9b59b6,
+			Timestamp:   time.Now().Format(time.RFC3339),
+		}
+
+		if len(recommended) == 0 {
+			embed.Description += "\n\nおすすめできる問題が見つかりませんでした。"
+		} else {
+			// The links are too long for a 1024-character field, so the list goes in the description
+			var sb strings.Builder
+			sb.WriteString("\n\n")
+			for idx, r := range recommended {
+				sb.WriteString(fmt.Sprintf("%d. [%s](%s) - 難易度 %d / 正解確率 %.0f%%\n",
+					idx+1, r.problem.Title, virtual.ProblemURL(r.problem),
+					r.problem.Difficulty.Int64, r.probability*100))
+			}
+			embed.Description += sb.String()
+		}
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{embed},
+				Flags:  discordgo.MessageFlagsEphemeral,
+			},
+		})
+	}
+}
+
+// recommendation is a candidate problem with its predicted solve probability
+type recommendation struct {
+	problem     *models.Problem
+	probability float64
+}
+
+// estimateUserRating estimates a user's ability from their submission history
+func estimateUserRating(attempts []*models.ProblemAttempt) float64 {
+	irtAttempts := make([]atcoder.IRTAttempt, 0, len(attempts))
+	for _, a := range attempts {
+		irtAttempts = append(irtAttempts, atcoder.IRTAttempt{
+			Difficulty:     float64(a.Difficulty.Int64),
+			Discrimination: a.Discrimination.Float64,
+			Solved:         a.Solved,
+		})
+	}
+	return atcoder.EstimateRating(irtAttempts)
+}
+
+// recommendProblems picks the problems whose solve probability is closest to the target
+func recommendProblems(candidates []*models.Problem, rating, target float64, count int) []recommendation {
+	recs := make([]recommendation, 0, len(candidates))
+	for _, p := range candidates {
+		recs = append(recs, recommendation{
+			problem:     p,
+			probability: atcoder.PredictSolveProbability(rating, float64(p.Difficulty.Int64), p.Discrimination.Float64),
+		})
+	}
+
+	sort.SliceStable(recs, func(a, b int) bool {
+		return math.Abs(recs[a].probability-target) < math.Abs(recs[b].probability-target)
+	})
+
+	if len(recs) > count {
+		recs = recs[:count]
+	}
+	return recs
+}
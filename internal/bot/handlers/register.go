@@ -1,17 +1,21 @@
 package handlers
 
 import (
+	"database/sql"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"coding-winner/internal/atcoder"
+	"coding-winner/internal/backfill"
 	"coding-winner/internal/database"
 	"coding-winner/internal/database/queries"
 	"coding-winner/internal/models"
 )
 
-// HandleRegister handles the /register command
+// HandleRegister handles the /register command. The account is registered right away so that
+// its submissions are synced, but it stays out of rankings until ownership is confirmed with /verify.
 func HandleRegister(db *database.DB, atcoderClient *atcoder.Client) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 		// Get username option
@@ -21,8 +25,20 @@ func HandleRegister(db *database.DB, atcoderClient *atcoder.Client) func(s *disc
 		// Get Discord user ID
 		discordID := i.Member.User.ID
 
+		// An account verified by another member cannot be claimed
+		owner, err := queries.GetUserByAtCoderUsername(db, username)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		if owner != nil && owner.DiscordID != discordID && owner.Verified {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ AtCoderユーザー `%s` は別のメンバーが本人確認済みで登録しています。", username))
+		}
+		if owner != nil && owner.DiscordID == discordID && owner.Verified {
+			return respondEphemeral(s, i, fmt.Sprintf("✅ AtCoderユーザー `%s` は登録・本人確認済みです。", username))
+		}
+
 		// Send initial response immediately
-		err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
 				Content: fmt.Sprintf("AtCoderユーザー `%s` を確認中...", username),
@@ -48,6 +64,34 @@ func HandleRegister(db *database.DB, atcoderClient *atcoder.Client) func(s *disc
 				return
 			}
 
+			token, err := atcoder.NewVerificationToken()
+			if err != nil {
+				log.Printf("Error generating verification token: %v", err)
+				updateResponse(s, i, "❌ ユーザー登録に失敗しました。")
+				return
+			}
+			verification := &models.UserVerification{
+				DiscordID:       discordID,
+				AtCoderUsername: username,
+				Token:           token,
+			}
+			if err := queries.SaveUserVerification(db, verification); err != nil {
+				log.Printf("Error saving verification for %s: %v", username, err)
+				updateResponse(s, i, "❌ ユーザー登録に失敗しました。")
+				return
+			}
+
+			verifyGuide := fmt.Sprintf("本人確認のため、AtCoderの設定（%s/settings）で**所属**に `%s` を含めて保存し、"+
+				"`/verify` を実行してください。確認が済むまでランキングには表示されません（確認後は所属を元に戻して構いません）。",
+				atcoder.AtCoderBaseURL, token)
+
+			// Another member's unverified claim is only replaced once this member proves ownership
+			if owner != nil && owner.DiscordID != discordID {
+				updateResponse(s, i, fmt.Sprintf("⚠️ AtCoderユーザー `%s` は別のメンバーが本人確認前の状態で登録しています。\n%s",
+					username, verifyGuide))
+				return
+			}
+
 			// Create/update user in database
 			user := &models.User{
 				DiscordID:       discordID,
@@ -61,30 +105,133 @@ func HandleRegister(db *database.DB, atcoderClient *atcoder.Client) func(s *disc
 			}
 
 			// Update response to success
-			updateResponse(s, i,
-				fmt.Sprintf("✅ AtCoderユーザー `%s` を登録しました！\n"+
-					"過去の提出履歴を同期中です...", username))
+			header := fmt.Sprintf("✅ AtCoderユーザー `%s` を登録しました！\n%s\n\n", username, verifyGuide)
+			updateResponse(s, i, header+"過去の提出履歴を同期中です...")
+
+			backfillSubmissions(s, i, db, atcoderClient, user, header)
+		}()
+
+		return nil
+	}
+}
+
+// HandleVerify handles the /verify command, confirming that the member owns the AtCoder
+// account they registered by finding their token in its affiliation
+func HandleVerify(db *database.DB, atcoderClient *atcoder.Client, fetcher atcoder.ProfileFetcher) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		discordID := i.Member.User.ID
+
+		verification, err := queries.GetUserVerification(db, discordID)
+		if err == sql.ErrNoRows {
+			return respondEphemeral(s, i, "❌ 本人確認待ちの登録がありません。先に `/register` を実行してください。")
+		}
+		if err != nil {
+			return err
+		}
 
-			// Sync initial submissions in background
-			log.Printf("Starting initial sync for user %s", username)
-			submissions, err := atcoderClient.SyncUserSubmissions(username, discordID, nil)
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("AtCoderユーザー `%s` のプロフィールを確認中...", verification.AtCoderUsername),
+				Flags:   discordgo.MessageFlagsEphemeral,
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		go func() {
+			ok, err := atcoder.VerifyOwnership(fetcher, verification.AtCoderUsername, verification.Token)
 			if err != nil {
-				log.Printf("Error syncing submissions for %s: %v", username, err)
+				log.Printf("Error verifying %s: %v", verification.AtCoderUsername, err)
+				updateResponse(s, i, "❌ AtCoderのプロフィールを取得できませんでした。後でもう一度お試しください。")
+				return
+			}
+			if !ok {
+				updateResponse(s, i, fmt.Sprintf("❌ 所属に `%s` が見つかりませんでした。%s/settings で所属を保存してから、もう一度 `/verify` を実行してください。",
+					verification.Token, atcoder.AtCoderBaseURL))
+				return
+			}
+
+			// Accounts that were not registered to this member yet need their history synced
+			previous, err := queries.GetUser(db, discordID)
+			needsSync := err != nil || previous.AtCoderUsername != verification.AtCoderUsername
+
+			if err := confirmVerification(db, verification); err != nil {
+				log.Printf("Error confirming verification for %s: %v", verification.AtCoderUsername, err)
+				updateResponse(s, i, "❌ 本人確認の保存に失敗しました。")
 				return
 			}
 
-			if err := queries.CreateSubmissions(db, submissions); err != nil {
-				log.Printf("Error saving submissions for %s: %v", username, err)
+			header := fmt.Sprintf("✅ AtCoderユーザー `%s` の本人確認ができました！ランキングに表示されます。\n"+
+				"所属は元に戻して構いません。", verification.AtCoderUsername)
+			if !needsSync {
+				updateResponse(s, i, header)
 				return
 			}
 
-			log.Printf("Synced %d submissions for user %s", len(submissions), username)
+			updateResponse(s, i, header+"\n\n過去の提出履歴を同期中です...")
+			backfillSubmissions(s, i, db, atcoderClient, &models.User{
+				DiscordID:       discordID,
+				AtCoderUsername: verification.AtCoderUsername,
+			}, header+"\n\n")
 		}()
 
 		return nil
 	}
 }
 
+// confirmVerification saves a verified claim in one transaction, so that another member's
+// unverified registration is only removed once the claimant is verified
+func confirmVerification(db *database.DB, verification *models.UserVerification) error {
+	tx, err := db.Beginx()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := queries.ConfirmUserVerification(tx, verification); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// backfillSubmissions syncs the whole submission history of a newly registered account,
+// showing its progress below header in the interaction response. If the bot restarts
+// midway, the scheduler resumes the backfill from its saved cursor.
+func backfillSubmissions(s *discordgo.Session, i *discordgo.InteractionCreate, db *database.DB, atcoderClient *atcoder.Client, user *models.User, header string) {
+	pending, err := backfill.IsPending(db, user)
+	if err != nil {
+		log.Printf("Error getting backfill state for %s: %v", user.AtCoderUsername, err)
+	}
+	if err == nil && !pending {
+		if state, err := queries.GetSubmissionBackfill(db, user.DiscordID); err == nil {
+			updateResponse(s, i, fmt.Sprintf("%s✅ 過去の提出履歴は同期済みです（%d件）。", header, state.Fetched))
+			return
+		}
+	}
+
+	log.Printf("Starting submission backfill for user %s", user.AtCoderUsername)
+	fetched := 0
+	started, err := backfill.Run(db, atcoderClient, user, func(n int, reached time.Time) {
+		fetched = n
+		updateResponse(s, i, fmt.Sprintf("%s過去の提出履歴を同期中です... %d件（%s まで）",
+			header, n, reached.Format("2006/01/02")))
+	})
+	if err != nil {
+		log.Printf("Error backfilling submissions for %s: %v", user.AtCoderUsername, err)
+		updateResponse(s, i, fmt.Sprintf("%s⚠️ 提出履歴の同期が %d件 で中断しました。続きは自動で再開されます。", header, fetched))
+		return
+	}
+	if !started {
+		updateResponse(s, i, header+"過去の提出履歴は同期中です。")
+		return
+	}
+
+	updateResponse(s, i, fmt.Sprintf("%s✅ 過去の提出履歴を同期しました（%d件）。", header, fetched))
+	log.Printf("Backfilled %d submissions for user %s", fetched, user.AtCoderUsername)
+}
+
 // updateResponse updates the interaction response
 func updateResponse(s *discordgo.Session, i *discordgo.InteractionCreate, content string) error {
 	_, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// syncStatusTimeLayout formats times shown by /sync-status
+const syncStatusTimeLayout = "2006/01/02 15:04"
+
+// HandleSyncStatus handles the /sync-status command. It shows when a member's submissions
+// were last synced, or with failing:true lists the members whose sync is failing (admins only).
+func HandleSyncStatus(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		discordID := i.Member.User.ID
+		failing := false
+		for _, opt := range i.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "user":
+				discordID = opt.UserValue(s).ID
+			case "failing":
+				failing = opt.BoolValue()
+			}
+		}
+
+		if failing {
+			if i.Member.Permissions&(discordgo.PermissionAdministrator|discordgo.PermissionManageServer) == 0 {
+				return respondEphemeral(s, i, "❌ 同期に失敗しているユーザーの一覧は管理者のみ表示できます。")
+			}
+			return respondFailingSyncs(s, i, db)
+		}
+
+		user, err := queries.GetUser(db, discordID)
+		if err != nil {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ <@%s> はユーザー登録されていません。", discordID))
+		}
+
+		state, err := queries.GetUserSyncState(db, discordID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+		backfill, err := queries.GetSubmissionBackfill(db, discordID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title:  fmt.Sprintf("🔄 %s の同期状況", user.AtCoderUsername),
+			Color:  0x3498db,
+			Fields: syncStatusFields(user, state, backfill),
+		}
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{embed},
+				Flags:  discordgo.MessageFlagsEphemeral,
+			},
+		})
+	}
+}
+
+// syncStatusFields describes a member's sync state and history backfill
+func syncStatusFields(user *models.User, state *models.UserSyncState, backfill *models.SubmissionBackfill) []*discordgo.MessageEmbedField {
+	lastSuccess := "まだ同期されていません"
+	cursor := "-"
+	failures := "なし"
+	if state != nil {
+		if state.LastSuccessAt.Valid {
+			lastSuccess = state.LastSuccessAt.Time.In(virtual.JST).Format(syncStatusTimeLayout)
+		}
+		if state.LastSyncedEpoch.Valid {
+			cursor = time.Unix(state.LastSyncedEpoch.Int64, 0).In(virtual.JST).Format(syncStatusTimeLayout) + " 以降を次回取得"
+		}
+		if state.ConsecutiveFailures > 0 {
+			failures = fmt.Sprintf("%d回連続で失敗（%s）\n`%s`", state.ConsecutiveFailures,
+				state.LastErrorAt.Time.In(virtual.JST).Format(syncStatusTimeLayout), truncateSyncError(state.LastError.String))
+		}
+	}
+
+	history := "未開始"
+	if backfill != nil && backfill.AtCoderUsername == user.AtCoderUsername {
+		if backfill.CompletedAt.Valid {
+			history = fmt.Sprintf("完了（%d件・%s）", backfill.Fetched, backfill.CompletedAt.Time.In(virtual.JST).Format(syncStatusTimeLayout))
+		} else {
+			history = fmt.Sprintf("取得中（%d件・%s まで）", backfill.Fetched,
+				time.Unix(backfill.FromSecond, 0).In(virtual.JST).Format("2006/01/02"))
+		}
+	}
+
+	return []*discordgo.MessageEmbedField{
+		{Name: "最終同期", Value: lastSuccess, Inline: true},
+		{Name: "同期位置", Value: cursor, Inline: true},
+		{Name: "過去の提出履歴", Value: history},
+		{Name: "エラー", Value: failures},
+	}
+}
+
+// respondFailingSyncs lists the members of the server whose latest syncs failed
+func respondFailingSyncs(s *discordgo.Session, i *discordgo.InteractionCreate, db *database.DB) error {
+	states, err := queries.GetFailingUserSyncStates(db)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	for _, state := range states {
+		if !isGuildMember(s, i.GuildID, state.UserID) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("**%s**（<@%s>）: %d回連続・最終 %s\n`%s`",
+			state.AtCoderUsername, state.UserID, state.ConsecutiveFailures,
+			state.LastErrorAt.Time.In(virtual.JST).Format(syncStatusTimeLayout), truncateSyncError(state.LastError.String)))
+		if len(lines) >= 10 {
+			break
+		}
+	}
+	if len(lines) == 0 {
+		return respondEphemeral(s, i, "✅ 同期に失敗しているユーザーはいません。")
+	}
+
+	return respondEphemeral(s, i, "⚠️ 同期に失敗しているユーザー\n\n"+strings.Join(lines, "\n"))
+}
+
+// truncateSyncError shortens an error message to fit a list line or an embed field
+func truncateSyncError(message string) string {
+	const maxLen = 80
+	runes := []rune(message)
+	if len(runes) <= maxLen {
+		return message
+	}
+	return string(runes[:maxLen]) + "..."
+}
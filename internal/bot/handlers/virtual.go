@@ -3,6 +3,7 @@ package handlers
 import (
 	"database/sql"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,6 +11,7 @@ import (
 	"coding-winner/internal/database"
 	"coding-winner/internal/database/queries"
 	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
 )
 
 // HandleVirtualCreate handles the /virtual-create command
@@ -21,16 +23,13 @@ func HandleVirtualCreate(db *database.DB) func(s *discordgo.Session, i *discordg
 		duration := int(options[1].IntValue())
 		problemsStr := options[2].StringValue()
 
-		// Parse problem IDs
-		problemIDs := strings.Split(strings.ReplaceAll(problemsStr, " ", ""), ",")
-		if len(problemIDs) == 0 {
-			return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "❌ 問題IDを指定してください。",
-					Flags:   discordgo.MessageFlagsEphemeral,
-				},
-			})
+		// Resolve problems against the problems table
+		problemIDs, errMsg, err := resolveProblemInput(db, problemsStr)
+		if err != nil {
+			return err
+		}
+		if errMsg != "" {
+			return respondEphemeral(s, i, errMsg)
 		}
 
 		// Create virtual contest
@@ -39,9 +38,27 @@ func HandleVirtualCreate(db *database.DB) func(s *discordgo.Session, i *discordg
 			ChannelID:       i.ChannelID,
 			CreatedBy:       sql.NullString{String: i.Member.User.ID, Valid: true},
 			Title:           title,
-			StartTime:       time.Now(), // Will be updated when started
 			DurationMinutes: duration,
 			ProblemIDs:      problemIDs,
+			Status:          models.VirtualContestDraft,
+			PenaltyRule:     models.PenaltyRuleAtCoder,
+			PresolvedPolicy: models.PresolvedWarn,
+		}
+
+		for _, opt := range options {
+			switch opt.Name {
+			case "start":
+				startTime, errMsg := parseFutureStartTime(opt.StringValue())
+				if errMsg != "" {
+					return respondEphemeral(s, i, errMsg)
+				}
+				contest.StartTime = sql.NullTime{Time: startTime, Valid: true}
+				contest.Status = models.VirtualContestScheduled
+			case "rule":
+				contest.PenaltyRule = opt.StringValue()
+			case "presolved":
+				contest.PresolvedPolicy = opt.StringValue()
+			}
 		}
 
 		contestID, err := queries.CreateVirtualContest(db, contest)
@@ -49,14 +66,169 @@ func HandleVirtualCreate(db *database.DB) func(s *discordgo.Session, i *discordg
 			return err
 		}
 
+		contest.ID = contestID
+		message := fmt.Sprintf("✅ バーチャルコンテスト「%s」を作成しました。\n", title) +
+			virtual.FormatCreatedMessage(contest, nil)
+
 		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: fmt.Sprintf("✅ バーチャルコンテスト「%s」を作成しました。\n"+
-					"コンテストID: %d\n"+
-					"時間: %d分\n"+
-					"問題数: %d\n\n"+
-					"`/virtual-start %d` で開始してください。", title, contestID, duration, len(problemIDs), contestID),
+				Content:    message,
+				Components: virtual.ParticipationButtons(contestID),
+			},
+		})
+	}
+}
+
+// HandleVirtualAuto handles the /virtual-auto command
+func HandleVirtualAuto(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		count := 5
+		rangeStr := ""
+		title := ""
+		duration := 100
+		contest := &models.VirtualContest{
+			ServerID:    i.GuildID,
+			ChannelID:   i.ChannelID,
+			CreatedBy:   sql.NullString{String: i.Member.User.ID, Valid: true},
+			Status:      models.VirtualContestDraft,
+			PenaltyRule: models.PenaltyRuleAtCoder,
+		}
+
+		for _, opt := range i.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "count":
+				count = int(opt.IntValue())
+			case "range":
+				rangeStr = opt.StringValue()
+			case "title":
+				title = opt.StringValue()
+			case "duration":
+				duration = int(opt.IntValue())
+			case "start":
+				startTime, errMsg := parseFutureStartTime(opt.StringValue())
+				if errMsg != "" {
+					return respondEphemeral(s, i, errMsg)
+				}
+				contest.StartTime = sql.NullTime{Time: startTime, Valid: true}
+				contest.Status = models.VirtualContestScheduled
+			case "rule":
+				contest.PenaltyRule = opt.StringValue()
+			case "presolved":
+				contest.PresolvedPolicy = opt.StringValue()
+			}
+		}
+
+		if count < 1 || count > 26 {
+			return respondEphemeral(s, i, "❌ 問題数は1〜26で指定してください。")
+		}
+		minDiff, maxDiff, err := virtual.ParseDifficultyRange(rangeStr)
+		if err != nil {
+			return respondEphemeral(s, i, "❌ 難易度の範囲は `400-1600` の形式で指定してください。")
+		}
+
+		// Exclude problems solved by any registered member of this server
+		memberIDs, err := guildMemberUserIDs(s, db, i.GuildID)
+		if err != nil {
+			return err
+		}
+
+		candidates, err := queries.GetProblemsUnsolvedByAll(db, memberIDs, minDiff, maxDiff)
+		if err != nil {
+			return err
+		}
+		if len(candidates) < count {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ 条件に合う未解決の問題が%d問しかありません。範囲を広げてください。", len(candidates)))
+		}
+
+		problems := virtual.PickIncreasingDifficulty(candidates, count, minDiff, maxDiff)
+		contest.ProblemIDs, contest.ProblemPoints = virtual.AutoProblemSet(problems)
+
+		if title == "" {
+			title = fmt.Sprintf("自動生成バーチャル（%d-%d）", minDiff, maxDiff)
+		}
+		contest.Title = title
+		contest.DurationMinutes = duration
+
+		contestID, err := queries.CreateVirtualContest(db, contest)
+		if err != nil {
+			return err
+		}
+
+		contest.ID = contestID
+		extraLines := []string{fmt.Sprintf("難易度: %d-%d（参加者全員が未解決の問題）\n", minDiff, maxDiff)}
+		for idx, p := range problems {
+			extraLines = append(extraLines, fmt.Sprintf("%s. %s（%d点・diff %d）", virtual.ProblemLabel(idx), p.Title,
+				int(contest.ProblemPoints[idx]), p.Difficulty.Int64))
+		}
+		message := fmt.Sprintf("✅ バーチャルコンテスト「%s」を自動生成しました。\n", title) +
+			virtual.FormatCreatedMessage(contest, extraLines)
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content:    message,
+				Components: virtual.ParticipationButtons(contestID),
+			},
+		})
+	}
+}
+
+// HandleVirtualReplay handles the /virtual-replay command
+func HandleVirtualReplay(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		atcoderContestID := ""
+		contest := &models.VirtualContest{
+			ServerID:    i.GuildID,
+			ChannelID:   i.ChannelID,
+			CreatedBy:   sql.NullString{String: i.Member.User.ID, Valid: true},
+			Status:      models.VirtualContestDraft,
+			PenaltyRule: models.PenaltyRuleAtCoder,
+		}
+
+		for _, opt := range i.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "contest":
+				atcoderContestID = virtual.ParseContestID(opt.StringValue())
+			case "start":
+				startTime, errMsg := parseFutureStartTime(opt.StringValue())
+				if errMsg != "" {
+					return respondEphemeral(s, i, errMsg)
+				}
+				contest.StartTime = sql.NullTime{Time: startTime, Valid: true}
+				contest.Status = models.VirtualContestScheduled
+			case "rule":
+				contest.PenaltyRule = opt.StringValue()
+			case "presolved":
+				contest.PresolvedPolicy = opt.StringValue()
+			}
+		}
+
+		original, err := virtual.SetupReplay(db, contest, atcoderContestID)
+		if err == sql.ErrNoRows {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ コンテスト `%s` が見つかりません（コンテスト情報は毎日3時に同期されます）。", atcoderContestID))
+		}
+		if err == virtual.ErrReplayNoProblems {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ コンテスト `%s` の問題が見つかりません。", atcoderContestID))
+		}
+		if err != nil {
+			return err
+		}
+
+		contestID, err := queries.CreateVirtualContest(db, contest)
+		if err != nil {
+			return err
+		}
+
+		contest.ID = contestID
+		message := fmt.Sprintf("✅ 「%s」のリプレイを作成しました。\n", original.Title) +
+			virtual.FormatCreatedMessage(contest, []string{"終了後に推定パフォーマンスをお知らせします。"})
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content:    message,
+				Components: virtual.ParticipationButtons(contestID),
 			},
 		})
 	}
@@ -70,50 +242,193 @@ func HandleVirtualStart(db *database.DB) func(s *discordgo.Session, i *discordgo
 
 		// Get contest
 		contest, err := queries.GetVirtualContest(db, contestID)
+		if err != nil || contest.ServerID != i.GuildID {
+			return respondEphemeral(s, i, "❌ このサーバーに該当するコンテストがありません。")
+		}
+
+		if contest.Status != models.VirtualContestDraft && contest.Status != models.VirtualContestScheduled {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ このコンテストは開始できません（%s）。", virtual.StatusLabel(contest.Status)))
+		}
+
+		problems, err := virtual.LoadProblems(db, contest)
 		if err != nil {
 			return err
 		}
 
-		// Check if contest belongs to this server
-		if contest.ServerID != i.GuildID {
-			return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-				Type: discordgo.InteractionResponseChannelMessageWithSource,
-				Data: &discordgo.InteractionResponseData{
-					Content: "❌ このコンテストはこのサーバーのものではありません。",
-					Flags:   discordgo.MessageFlagsEphemeral,
-				},
-			})
+		// Start now and persist the start time, unless the scheduler started it first
+		now := time.Now()
+		started, err := queries.StartVirtualContest(db, contest.ID, now)
+		if err != nil {
+			return err
+		}
+		if !started {
+			return respondEphemeral(s, i, "❌ このコンテストはすでに開始されています。")
+		}
+		contest.StartTime = sql.NullTime{Time: now, Valid: true}
+		contest.Status = models.VirtualContestRunning
+
+		if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content:    virtual.FormatStartMessage(contest, problems),
+				Components: virtual.ParticipationButtons(contest.ID),
+			},
+		}); err != nil {
+			return err
 		}
 
-		// Update start time
-		contest.StartTime = time.Now()
+		return virtual.PostLiveStandings(s, db, contest)
+	}
+}
 
-		// Build problem list message
-		var problemsList strings.Builder
-		for i, pid := range contest.ProblemIDs {
-			problemsList.WriteString(fmt.Sprintf("%d. %s (https://atcoder.jp/contests/%s/tasks/%s)\n",
-				i+1, pid, strings.Split(pid, "_")[0], pid))
+// HandleVirtualJoin handles the Join button on virtual contest announcements.
+// Members joining a running contest start late, with their own start offset.
+func HandleVirtualJoin(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		contest, err := getButtonContest(db, i)
+		if err != nil {
+			return err
 		}
 
-		endTime := contest.StartTime.Add(time.Duration(contest.DurationMinutes) * time.Minute)
+		user, err := queries.GetUser(db, i.Member.User.ID)
+		if err != nil {
+			return respondEphemeral(s, i, "❌ ユーザー登録されていません。`/register` コマンドで登録してください。")
+		}
+		if !user.Verified {
+			return respondEphemeral(s, i, unverifiedJoinMessage)
+		}
 
-		message := fmt.Sprintf("🏁 **バーチャルコンテスト開始！**\n\n"+
-			"**タイトル**: %s\n"+
-			"**時間**: %d分\n"+
-			"**終了時刻**: %s\n\n"+
-			"**問題**:\n%s\n"+
-			"頑張ってください！",
-			contest.Title, contest.DurationMinutes, endTime.Format("15:04"), problemsList.String())
+		offset, errMsg := joinStartOffset(contest)
+		if errMsg != "" {
+			return respondEphemeral(s, i, errMsg)
+		}
 
-		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
-			Type: discordgo.InteractionResponseChannelMessageWithSource,
-			Data: &discordgo.InteractionResponseData{
-				Content: message,
-			},
-		})
+		joined, err := queries.JoinVirtualContest(db, contest.ID, i.Member.User.ID, offset)
+		if err != nil {
+			return err
+		}
+		if !joined {
+			return respondEphemeral(s, i, "すでに参加しています。")
+		}
+
+		message := fmt.Sprintf("✅ 「%s」に参加しました。", contest.Title)
+		if offset > 0 {
+			message += fmt.Sprintf("\n途中参加のため、経過時間は参加時刻から計測します（終了は %s）。",
+				virtual.EndTime(contest).In(virtual.JST).Format("15:04"))
+		}
+		warning, err := presolvedWarning(db, contest, i.Member.User.ID)
+		if err != nil {
+			return err
+		}
+		message += warning
+		if err := respondEphemeral(s, i, message); err != nil {
+			return err
+		}
+		return refreshLiveStandings(s, db, contest)
+	}
+}
+
+// HandleVirtualLeave handles the Leave button on virtual contest announcements
+func HandleVirtualLeave(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		contest, err := getButtonContest(db, i)
+		if err != nil {
+			return err
+		}
+
+		// Leaving after the start would drop the member's results, and with them a rating loss
+		if contest.Status != models.VirtualContestDraft && contest.Status != models.VirtualContestScheduled {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ 開始後は参加を取り消せません（%s）。", virtual.StatusLabel(contest.Status)))
+		}
+
+		left, err := queries.LeaveVirtualContest(db, contest.ID, i.Member.User.ID)
+		if err != nil {
+			return err
+		}
+		if !left {
+			return respondEphemeral(s, i, "このコンテストには参加していないか、すでに開始しています。")
+		}
+
+		if err := respondEphemeral(s, i, fmt.Sprintf("「%s」への参加を取り消しました。", contest.Title)); err != nil {
+			return err
+		}
+		return refreshLiveStandings(s, db, contest)
+	}
+}
+
+// unverifiedJoinMessage is shown to members who try to join before verifying their AtCoder account
+const unverifiedJoinMessage = "❌ AtCoderアカウントの本人確認が済んでいないため参加できません。`/verify` で本人確認をしてください。"
+
+// joinStartOffset returns the start offset in seconds for a member joining the contest now.
+// On failure it returns a user-facing error message.
+func joinStartOffset(contest *models.VirtualContest) (int, string) {
+	switch contest.Status {
+	case models.VirtualContestDraft, models.VirtualContestScheduled:
+		return 0, ""
+	case models.VirtualContestRunning:
+		return int(time.Since(contest.StartTime.Time).Seconds()), ""
+	default:
+		return 0, fmt.Sprintf("❌ このコンテストには参加できません（%s）。", virtual.StatusLabel(contest.Status))
 	}
 }
 
+// presolvedWarning warns a joining user about contest problems they have already solved.
+// It returns an empty string if there are none.
+func presolvedWarning(db *database.DB, contest *models.VirtualContest, userID string) (string, error) {
+	solved, err := queries.GetSolvedProblemIDsBefore(db, userID, contest.ProblemIDs, time.Now())
+	if err != nil {
+		return "", err
+	}
+	if len(solved) == 0 {
+		return "", nil
+	}
+
+	solvedSet := make(map[string]bool, len(solved))
+	for _, pid := range solved {
+		solvedSet[pid] = true
+	}
+	var labels []string
+	for idx, pid := range contest.ProblemIDs {
+		if solvedSet[pid] {
+			labels = append(labels, virtual.ProblemLabel(idx))
+		}
+	}
+
+	warning := fmt.Sprintf("\n⚠️ 次の問題はすでにAC済みです: %s", strings.Join(labels, ", "))
+	if contest.PresolvedPolicy == models.PresolvedExclude {
+		warning += "（このコンテストでは得点に含まれません）"
+	} else {
+		warning += "（順位表に印が付きます）"
+	}
+	return warning, nil
+}
+
+// getButtonContest loads the contest referenced by a virtual-join/virtual-leave custom ID
+func getButtonContest(db *database.DB, i *discordgo.InteractionCreate) (*models.VirtualContest, error) {
+	// Custom ID format: virtual-join:<contest id>
+	parts := strings.Split(i.MessageComponentData().CustomID, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid virtual contest custom ID: %s", i.MessageComponentData().CustomID)
+	}
+	contestID, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return nil, err
+	}
+	return queries.GetVirtualContest(db, contestID)
+}
+
+// refreshLiveStandings redraws the live standings of a running contest after its participants change
+func refreshLiveStandings(s *discordgo.Session, db *database.DB, contest *models.VirtualContest) error {
+	if contest.Status != models.VirtualContestRunning {
+		return nil
+	}
+	standings, err := virtual.GetStandings(db, contest)
+	if err != nil {
+		return err
+	}
+	return virtual.UpdateLiveStandings(s, contest, standings)
+}
+
 // HandleVirtualStandings handles the /virtual-standings command
 func HandleVirtualStandings(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
 	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
@@ -127,29 +442,184 @@ func HandleVirtualStandings(db *database.DB) func(s *discordgo.Session, i *disco
 		}
 
 		// Get standings
-		standings, err := queries.GetVirtualContestStandings(db, contestID)
+		standings, err := virtual.GetStandings(db, contest)
 		if err != nil {
 			return err
 		}
 
 		// Build standings message
 		var sb strings.Builder
-		sb.WriteString(fmt.Sprintf("📊 **%s - 順位表**\n\n", contest.Title))
-
-		if len(standings) == 0 {
-			sb.WriteString("まだ提出がありません。")
-		} else {
-			for _, standing := range standings {
-				sb.WriteString(fmt.Sprintf("%d. **%s** - %d問正解 (%.0f点)\n",
-					standing.Rank, standing.AtCoderUsername, standing.SolvedCount, standing.TotalPoints))
+		sb.WriteString(fmt.Sprintf("📊 **%s - 順位表**（%s）\n\n", contest.Title, virtual.StatusLabel(contest.Status)))
+		sb.WriteString(virtual.FormatStandings(contest, standings))
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: sb.String(),
+			},
+		})
+	}
+}
+
+// HandleVirtualCancel handles the /virtual-cancel command
+func HandleVirtualCancel(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		contestID := int(i.ApplicationCommandData().Options[0].IntValue())
+
+		contest, err := queries.GetVirtualContest(db, contestID)
+		if err != nil || contest.ServerID != i.GuildID {
+			return respondEphemeral(s, i, "❌ このサーバーに該当するコンテストがありません。")
+		}
+		if !canManageVirtualContest(i, contest) {
+			return respondEphemeral(s, i, "❌ コンテストの作成者または管理者のみ中止できます。")
+		}
+
+		switch contest.Status {
+		case models.VirtualContestFinished, models.VirtualContestCancelled:
+			return respondEphemeral(s, i, fmt.Sprintf("❌ このコンテストは中止できません（%s）。", virtual.StatusLabel(contest.Status)))
+		}
+
+		ok, err := queries.UpdateVirtualContestStatus(db, contest.ID, contest.Status, models.VirtualContestCancelled)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return respondEphemeral(s, i, "❌ コンテストの状態が変更されたため中止できませんでした。もう一度お試しください。")
+		}
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("🚫 バーチャルコンテスト「%s」（ID: %d）を中止しました。", contest.Title, contest.ID),
+			},
+		})
+	}
+}
+
+// HandleVirtualEdit handles the /virtual-edit command
+func HandleVirtualEdit(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		options := i.ApplicationCommandData().Options
+		contestID := int(options[0].IntValue())
+
+		contest, err := queries.GetVirtualContest(db, contestID)
+		if err != nil || contest.ServerID != i.GuildID {
+			return respondEphemeral(s, i, "❌ このサーバーに該当するコンテストがありません。")
+		}
+		if !canManageVirtualContest(i, contest) {
+			return respondEphemeral(s, i, "❌ コンテストの作成者または管理者のみ編集できます。")
+		}
+		if contest.Status != models.VirtualContestDraft && contest.Status != models.VirtualContestScheduled {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ 開始前のコンテストのみ編集できます（%s）。", virtual.StatusLabel(contest.Status)))
+		}
+
+		for _, opt := range options {
+			switch opt.Name {
+			case "title":
+				contest.Title = opt.StringValue()
+			case "duration":
+				contest.DurationMinutes = int(opt.IntValue())
+			case "problems":
+				problemIDs, errMsg, err := resolveProblemInput(db, opt.StringValue())
+				if err != nil {
+					return err
+				}
+				if errMsg != "" {
+					return respondEphemeral(s, i, errMsg)
+				}
+				contest.ProblemIDs = problemIDs
+				contest.ProblemPoints = nil
+			case "start":
+				startTime, errMsg := parseFutureStartTime(opt.StringValue())
+				if errMsg != "" {
+					return respondEphemeral(s, i, errMsg)
+				}
+				contest.StartTime = sql.NullTime{Time: startTime, Valid: true}
+				contest.Status = models.VirtualContestScheduled
+			case "rule":
+				contest.PenaltyRule = opt.StringValue()
+			case "presolved":
+				contest.PresolvedPolicy = opt.StringValue()
 			}
 		}
 
+		updated, err := queries.UpdateVirtualContest(db, contest)
+		if err != nil {
+			return err
+		}
+		if !updated {
+			return respondEphemeral(s, i, "❌ 編集中にコンテストが開始または中止されたため、更新できませんでした。")
+		}
+
+		message := fmt.Sprintf("✅ バーチャルコンテスト「%s」（ID: %d）を更新しました。\n"+
+			"時間: %d分\n"+
+			"問題数: %d\n", contest.Title, contest.ID, contest.DurationMinutes, len(contest.ProblemIDs))
+		if contest.StartTime.Valid {
+			message += fmt.Sprintf("開始予定: %s", contest.StartTime.Time.In(virtual.JST).Format(virtual.StartTimeLayout))
+		}
+
 		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
 			Type: discordgo.InteractionResponseChannelMessageWithSource,
 			Data: &discordgo.InteractionResponseData{
-				Content: sb.String(),
+				Content: message,
 			},
 		})
 	}
 }
+
+// resolveProblemInput resolves a problem list entered by a user.
+// On failure it returns a user-facing error message listing the unknown entries.
+func resolveProblemInput(db *database.DB, input string) ([]string, string, error) {
+	problemIDs, unresolved, err := virtual.ResolveProblems(db, input)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(unresolved) > 0 {
+		return nil, "❌ 次の問題が見つかりませんでした。\n" + strings.Join(unresolved, "\n"), nil
+	}
+	if len(problemIDs) == 0 {
+		return nil, "❌ 問題を指定してください。", nil
+	}
+	return problemIDs, "", nil
+}
+
+// guildMemberUserIDs returns the Discord IDs of registered users who are members of the guild
+func guildMemberUserIDs(s *discordgo.Session, db *database.DB, guildID string) ([]string, error) {
+	users, err := queries.GetAllUsers(db)
+	if err != nil {
+		return nil, err
+	}
+	var memberIDs []string
+	for _, user := range users {
+		if isGuildMember(s, guildID, user.DiscordID) {
+			memberIDs = append(memberIDs, user.DiscordID)
+		}
+	}
+	return memberIDs, nil
+}
+
+// parseFutureStartTime parses a JST start time and requires it to be in the future.
+// On failure it returns a user-facing error message.
+func parseFutureStartTime(value string) (time.Time, string) {
+	startTime, err := virtual.ParseStartTime(value)
+	if err != nil {
+		return time.Time{}, "❌ 開始時刻は `2006-01-02 21:00` の形式（JST）で指定してください。"
+	}
+	if !startTime.After(time.Now()) {
+		return time.Time{}, "❌ 開始時刻には未来の時刻を指定してください。"
+	}
+	return startTime, ""
+}
+
+// canManageVirtualContest reports whether the invoking member created the contest or is a server admin
+func canManageVirtualContest(i *discordgo.InteractionCreate, contest *models.VirtualContest) bool {
+	if contest.CreatedBy.Valid && contest.CreatedBy.String == i.Member.User.ID {
+		return true
+	}
+	return isServerAdmin(i)
+}
+
+// isServerAdmin checks whether the member who sent the interaction can manage the server
+func isServerAdmin(i *discordgo.InteractionCreate) bool {
+	return i.Member.Permissions&(discordgo.PermissionAdministrator|discordgo.PermissionManageServer) != 0
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/atcoder"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// HandleVirtualImport handles the /virtual-import command.
+// The imported contest keeps its original start time: a future contest is scheduled, a running
+// one starts immediately, and a finished one is created as a draft to be started manually.
+func HandleVirtualImport(db *database.DB, atcoderClient *atcoder.Client) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		contest := &models.VirtualContest{
+			ServerID:    i.GuildID,
+			ChannelID:   i.ChannelID,
+			CreatedBy:   sql.NullString{String: i.Member.User.ID, Valid: true},
+			Status:      models.VirtualContestDraft,
+			PenaltyRule: models.PenaltyRuleAtCoder,
+		}
+
+		var input string
+		for _, opt := range i.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "contest":
+				input = opt.StringValue()
+			case "rule":
+				contest.PenaltyRule = opt.StringValue()
+			}
+		}
+
+		importID, err := atcoder.ParseVirtualContestID(input)
+		if err != nil {
+			return respondEphemeral(s, i, "❌ AtCoder ProblemsのバーチャルコンテストIDまたはURLを指定してください。")
+		}
+
+		// Fetching can take a while; defer the response
+		if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		}); err != nil {
+			return err
+		}
+
+		imported, err := atcoderClient.GetVirtualContest(importID)
+		if err != nil {
+			log.Printf("Error fetching AtCoder Problems virtual contest %s: %v", importID, err)
+			return updateResponse(s, i, "❌ AtCoder Problemsからコンテストを取得できませんでした。")
+		}
+		if len(imported.Problems) == 0 {
+			return updateResponse(s, i, "❌ このコンテストには問題がありません。")
+		}
+
+		// Every problem must be known; points are overridden only where the contest sets them
+		var problemIDs []string
+		var points []float64
+		hasOverride := false
+		for _, p := range imported.Problems {
+			problemIDs = append(problemIDs, p.ID)
+			if p.Point != nil {
+				points = append(points, *p.Point)
+				hasOverride = true
+			} else {
+				points = append(points, virtual.NoPointOverride)
+			}
+		}
+		known, err := queries.GetProblemsByIDs(db, problemIDs)
+		if err != nil {
+			return err
+		}
+		if len(known) != len(problemIDs) {
+			return updateResponse(s, i, "❌ 次の問題が見つかりませんでした。\n"+strings.Join(missingProblemIDs(problemIDs, known), "\n"))
+		}
+
+		contest.Title = imported.Info.Title
+		if contest.Title == "" {
+			contest.Title = "AtCoder Problems バーチャル"
+		}
+		contest.DurationMinutes = int(imported.Duration().Minutes())
+		contest.ProblemIDs = problemIDs
+		if hasOverride {
+			contest.ProblemPoints = points
+		}
+
+		now := time.Now()
+		startTime := imported.StartTime().Local()
+		endTime := startTime.Add(imported.Duration())
+		switch {
+		case startTime.After(now):
+			contest.StartTime = sql.NullTime{Time: startTime, Valid: true}
+			contest.Status = models.VirtualContestScheduled
+		case endTime.After(now):
+			contest.StartTime = sql.NullTime{Time: startTime, Valid: true}
+			contest.Status = models.VirtualContestRunning
+		}
+
+		contestID, err := queries.CreateVirtualContest(db, contest)
+		if err != nil {
+			return err
+		}
+		contest.ID = contestID
+
+		joined := joinImportedParticipants(db, contest, imported.Participants)
+
+		extraLines := []string{fmt.Sprintf("登録済みの参加者: %d人", joined)}
+		if !contest.StartTime.Valid {
+			extraLines = append(extraLines, "元のコンテストは終了しています。")
+		}
+		message := fmt.Sprintf("✅ AtCoder Problemsのコンテスト「%s」を取り込みました。\n", contest.Title) +
+			virtual.FormatCreatedMessage(contest, extraLines)
+
+		components := virtual.ParticipationButtons(contestID)
+		if _, err := s.InteractionResponseEdit(i.Interaction, &discordgo.WebhookEdit{
+			Content:    &message,
+			Components: &components,
+		}); err != nil {
+			return err
+		}
+
+		if contest.Status == models.VirtualContestRunning {
+			return virtual.PostLiveStandings(s, db, contest)
+		}
+		return nil
+	}
+}
+
+// joinImportedParticipants joins the registered users among the AtCoder Problems participants.
+// Users who are not registered or not verified are skipped. It returns the number of users joined.
+func joinImportedParticipants(db *database.DB, contest *models.VirtualContest, usernames []string) int {
+	joined := 0
+	for _, username := range usernames {
+		user, err := queries.GetUserByAtCoderUsername(db, username)
+		if err != nil || !user.Verified {
+			continue
+		}
+		if _, err := queries.JoinVirtualContest(db, contest.ID, user.DiscordID, 0); err != nil {
+			log.Printf("Error joining %s to virtual contest %d: %v", username, contest.ID, err)
+			continue
+		}
+		joined++
+	}
+	return joined
+}
+
+// missingProblemIDs lists the IDs that are not among the known problems
+func missingProblemIDs(problemIDs []string, known []*models.Problem) []string {
+	found := make(map[string]bool, len(known))
+	for _, p := range known {
+		found[p.ProblemID] = true
+	}
+	var missing []string
+	for _, id := range problemIDs {
+		if !found[id] {
+			missing = append(missing, "`"+id+"`")
+		}
+	}
+	return missing
+}
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// virtualListPageSize is the number of contests shown per page
+const virtualListPageSize = 10
+
+// virtualListStatuses maps each /virtual-list filter to the contest states it shows
+var virtualListStatuses = map[string][]string{
+	"upcoming": {models.VirtualContestDraft, models.VirtualContestScheduled},
+	"running":  {models.VirtualContestRunning},
+	"past":     {models.VirtualContestFinished, models.VirtualContestCancelled},
+	"all": {models.VirtualContestDraft, models.VirtualContestScheduled, models.VirtualContestRunning,
+		models.VirtualContestFinished, models.VirtualContestCancelled},
+}
+
+var virtualListLabels = map[string]string{
+	"upcoming": "開始前",
+	"running":  "開催中",
+	"past":     "過去",
+	"all":      "すべて",
+}
+
+// HandleVirtualList handles the /virtual-list command
+func HandleVirtualList(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		filter := "all"
+		for _, opt := range i.ApplicationCommandData().Options {
+			if opt.Name == "filter" {
+				filter = opt.StringValue()
+			}
+		}
+
+		if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseDeferredChannelMessageWithSource,
+		}); err != nil {
+			return err
+		}
+
+		return editVirtualList(db, s, i, filter, 0)
+	}
+}
+
+// HandleVirtualListPage handles the /virtual-list pagination buttons
+func HandleVirtualListPage(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		// Custom ID format: virtual-list:<filter>:<page>
+		args, page, err := handlePageButton(s, i, 1)
+		if err != nil {
+			return err
+		}
+		return editVirtualList(db, s, i, args[0], page)
+	}
+}
+
+// editVirtualList builds the requested page of the contest list and edits the deferred response
+func editVirtualList(db *database.DB, s *discordgo.Session, i *discordgo.InteractionCreate, filter string, page int) error {
+	statuses, ok := virtualListStatuses[filter]
+	if !ok {
+		filter, statuses = "all", virtualListStatuses["all"]
+	}
+
+	contests, err := queries.GetServerVirtualContests(db, i.GuildID, statuses)
+	if err != nil {
+		updateResponse(s, i, "❌ コンテスト一覧の取得に失敗しました。")
+		return err
+	}
+
+	page, totalPages := clampPage(page, len(contests), virtualListPageSize)
+	embed := buildVirtualListEmbed(contests, filter, page, totalPages)
+	return editPage(s, i, embed, "virtual-list:"+filter, page, totalPages)
+}
+
+// buildVirtualListEmbed builds an embed for one page of the contest list
+func buildVirtualListEmbed(contests []*models.VirtualContest, filter string, page, totalPages int) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:  fmt.Sprintf("📋 バーチャルコンテスト一覧（%s）", virtualListLabels[filter]),
+		Color:  0x3498db,
+		Footer: &discordgo.MessageEmbedFooter{Text: fmt.Sprintf("%d / %d ページ", page+1, totalPages)},
+	}
+
+	if len(contests) == 0 {
+		embed.Description = "該当するコンテストがありません。"
+		return embed
+	}
+
+	var sb strings.Builder
+	start := page * virtualListPageSize
+	end := start + virtualListPageSize
+	if end > len(contests) {
+		end = len(contests)
+	}
+	for _, contest := range contests[start:end] {
+		when := "開始時刻未定"
+		if contest.StartTime.Valid {
+			when = contest.StartTime.Time.In(virtual.JST).Format(virtual.StartTimeLayout)
+		}
+		sb.WriteString(fmt.Sprintf("**#%d %s**\n%s ・ %s ・ %d分 ・ %d問\n",
+			contest.ID, contest.Title, virtual.StatusLabel(contest.Status), when,
+			contest.DurationMinutes, len(contest.ProblemIDs)))
+	}
+	embed.Description = sb.String()
+
+	return embed
+}
+
+// HandleVirtualExport handles the /virtual-export command
+func HandleVirtualExport(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		var contestID int
+		format := "csv"
+		for _, opt := range i.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "contest-id":
+				contestID = int(opt.IntValue())
+			case "format":
+				format = opt.StringValue()
+			}
+		}
+
+		contest, err := queries.GetVirtualContest(db, contestID)
+		if err != nil || contest.ServerID != i.GuildID {
+			return respondEphemeral(s, i, "❌ このサーバーに該当するコンテストがありません。")
+		}
+
+		standings, err := virtual.GetStandings(db, contest)
+		if err != nil {
+			return err
+		}
+		timeline, err := virtual.LoadTimeline(db, contest)
+		if err != nil {
+			return err
+		}
+
+		baseName := fmt.Sprintf("virtual-%d", contest.ID)
+		var files []*discordgo.File
+		if format == "json" {
+			data, err := virtual.ExportJSON(contest, standings, timeline)
+			if err != nil {
+				return err
+			}
+			files = append(files, &discordgo.File{
+				Name:        baseName + ".json",
+				ContentType: "application/json",
+				Reader:      bytes.NewReader(data),
+			})
+		} else {
+			standingsCSV, timelineCSV, err := virtual.ExportCSV(contest, standings, timeline)
+			if err != nil {
+				return err
+			}
+			files = append(files,
+				&discordgo.File{
+					Name:        baseName + "-standings.csv",
+					ContentType: "text/csv",
+					Reader:      bytes.NewReader(standingsCSV),
+				},
+				&discordgo.File{
+					Name:        baseName + "-submissions.csv",
+					ContentType: "text/csv",
+					Reader:      bytes.NewReader(timelineCSV),
+				},
+			)
+		}
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Content: fmt.Sprintf("📦 「%s」の順位表と提出履歴（%s）", contest.Title, strings.ToUpper(format)),
+				Files:   files,
+			},
+		})
+	}
+}
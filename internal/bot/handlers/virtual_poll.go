@@ -0,0 +1,231 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// HandleVirtualPoll handles the /virtual-poll command. It posts candidate problem sets or
+// contests to replay as vote buttons; the scheduler closes the poll at the deadline and
+// creates the virtual contest from the winning option.
+func HandleVirtualPoll(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		choices := 3
+		deadlineMinutes := 60
+		count := 5
+		rangeStr := "400-1600"
+		series := "abc"
+		poll := &models.VirtualPoll{
+			ServerID:        i.GuildID,
+			ChannelID:       i.ChannelID,
+			CreatedBy:       sql.NullString{String: i.Member.User.ID, Valid: true},
+			DurationMinutes: 100,
+			PenaltyRule:     models.PenaltyRuleAtCoder,
+		}
+
+		for _, opt := range i.ApplicationCommandData().Options {
+			switch opt.Name {
+			case "mode":
+				poll.Mode = opt.StringValue()
+			case "choices":
+				choices = int(opt.IntValue())
+			case "deadline":
+				deadlineMinutes = int(opt.IntValue())
+			case "count":
+				count = int(opt.IntValue())
+			case "range":
+				rangeStr = opt.StringValue()
+			case "series":
+				series = opt.StringValue()
+			case "duration":
+				poll.DurationMinutes = int(opt.IntValue())
+			case "start":
+				startTime, errMsg := parseFutureStartTime(opt.StringValue())
+				if errMsg != "" {
+					return respondEphemeral(s, i, errMsg)
+				}
+				poll.StartTime = sql.NullTime{Time: startTime, Valid: true}
+			case "rule":
+				poll.PenaltyRule = opt.StringValue()
+			}
+		}
+
+		if choices < 2 || choices > virtual.MaxPollOptions {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ 候補数は2〜%dで指定してください。", virtual.MaxPollOptions))
+		}
+		if deadlineMinutes < 1 || deadlineMinutes > 7*24*60 {
+			return respondEphemeral(s, i, "❌ 締め切りは1分〜7日（10080分）で指定してください。")
+		}
+		poll.Deadline = time.Now().Add(time.Duration(deadlineMinutes) * time.Minute)
+		if poll.StartTime.Valid && !poll.StartTime.Time.After(poll.Deadline) {
+			return respondEphemeral(s, i, "❌ 開始時刻は投票の締め切りより後にしてください。")
+		}
+
+		// Candidates exclude anything solved by a registered member of this server
+		memberIDs, err := guildMemberUserIDs(s, db, i.GuildID)
+		if err != nil {
+			return err
+		}
+
+		var options []*models.VirtualPollOption
+		var errMsg string
+		if poll.Mode == models.VirtualPollReplay {
+			options, errMsg, err = replayPollOptions(db, memberIDs, series, choices)
+		} else {
+			options, errMsg, err = autoPollOptions(db, memberIDs, rangeStr, count, choices)
+		}
+		if err != nil {
+			return err
+		}
+		if errMsg != "" {
+			return respondEphemeral(s, i, errMsg)
+		}
+
+		poll.ID, err = queries.CreateVirtualPoll(db, poll, options)
+		if err != nil {
+			return err
+		}
+
+		err = s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{virtual.BuildPollEmbed(poll, options)},
+				Components: virtual.PollButtons(poll, options),
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		// Remember the message so the scheduler can close it at the deadline
+		msg, err := s.InteractionResponse(i.Interaction)
+		if err != nil {
+			return err
+		}
+		return queries.SetVirtualPollMessage(db, poll.ID, msg.ID)
+	}
+}
+
+// autoPollOptions generates disjoint problem sets of increasing difficulty from problems
+// none of the members has solved. On failure it returns a user-facing error message.
+func autoPollOptions(db *database.DB, memberIDs []string, rangeStr string, count, choices int) ([]*models.VirtualPollOption, string, error) {
+	if count < 1 || count > 10 {
+		return nil, "❌ 投票の問題数は1〜10で指定してください。", nil
+	}
+	minDiff, maxDiff, err := virtual.ParseDifficultyRange(rangeStr)
+	if err != nil {
+		return nil, "❌ 難易度の範囲は `400-1600` の形式で指定してください。", nil
+	}
+
+	candidates, err := queries.GetProblemsUnsolvedByAll(db, memberIDs, minDiff, maxDiff)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(candidates) < count*choices {
+		return nil, fmt.Sprintf("❌ 条件に合う未解決の問題が%d問しかありません（%d問×%d候補が必要です）。範囲を広げてください。",
+			len(candidates), count, choices), nil
+	}
+
+	var options []*models.VirtualPollOption
+	for idx := 0; idx < choices; idx++ {
+		problems := virtual.PickIncreasingDifficulty(candidates, count, minDiff, maxDiff)
+		ids, points := virtual.AutoProblemSet(problems)
+		options = append(options, &models.VirtualPollOption{
+			OptionIndex:   idx,
+			Label:         virtual.AutoPollOptionLabel(problems),
+			ProblemIDs:    ids,
+			ProblemPoints: points,
+		})
+		candidates = withoutProblems(candidates, problems)
+	}
+	return options, "", nil
+}
+
+// replayPollOptions picks past contests of a series in which none of the members solved a problem.
+// On failure it returns a user-facing error message.
+func replayPollOptions(db *database.DB, memberIDs []string, series string, choices int) ([]*models.VirtualPollOption, string, error) {
+	contests, err := queries.GetUnsolvedContests(db, memberIDs, series, choices)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(contests) < 2 {
+		return nil, fmt.Sprintf("❌ 全員が未参加の %s のコンテストが足りません。", strings.ToUpper(series)), nil
+	}
+
+	var options []*models.VirtualPollOption
+	for idx, contest := range contests {
+		options = append(options, &models.VirtualPollOption{
+			OptionIndex:     idx,
+			Label:           virtual.ReplayPollOptionLabel(contest),
+			ReplayContestID: sql.NullString{String: contest.ContestID, Valid: true},
+		})
+	}
+	return options, "", nil
+}
+
+// withoutProblems returns the candidates that are not in picked
+func withoutProblems(candidates, picked []*models.Problem) []*models.Problem {
+	used := make(map[string]bool)
+	for _, p := range picked {
+		used[p.ProblemID] = true
+	}
+	var remaining []*models.Problem
+	for _, p := range candidates {
+		if !used[p.ProblemID] {
+			remaining = append(remaining, p)
+		}
+	}
+	return remaining
+}
+
+// HandleVirtualPollVote handles the vote buttons of a virtual poll
+func HandleVirtualPollVote(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		// Custom ID format: virtual-poll:<poll id>:<option index>
+		parts := strings.Split(i.MessageComponentData().CustomID, ":")
+		if len(parts) != 3 {
+			return fmt.Errorf("invalid virtual-poll custom ID: %s", i.MessageComponentData().CustomID)
+		}
+		pollID, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return err
+		}
+		optionIndex, err := strconv.Atoi(parts[2])
+		if err != nil {
+			return err
+		}
+
+		poll, err := queries.GetVirtualPoll(db, pollID)
+		if err != nil {
+			return err
+		}
+		if poll.Closed || !time.Now().Before(poll.Deadline) {
+			return respondEphemeral(s, i, "❌ この投票は締め切られました。")
+		}
+
+		if err := queries.CastVirtualPollVote(db, poll.ID, i.Member.User.ID, optionIndex); err != nil {
+			return err
+		}
+
+		options, err := queries.GetVirtualPollOptions(db, poll.ID)
+		if err != nil {
+			return err
+		}
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseUpdateMessage,
+			Data: &discordgo.InteractionResponseData{
+				Embeds:     []*discordgo.MessageEmbed{virtual.BuildPollEmbed(poll, options)},
+				Components: virtual.PollButtons(poll, options),
+			},
+		})
+	}
+}
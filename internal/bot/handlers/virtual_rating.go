@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/virtual"
+)
+
+// ratingHistoryLimit is the number of recent contests shown by /vrating
+const ratingHistoryLimit = 10
+
+// HandleVirtualRating handles the /vrating command, showing a member's server-local rating and its history
+func HandleVirtualRating(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		discordID := i.Member.User.ID
+		options := i.ApplicationCommandData().Options
+		if len(options) > 0 {
+			discordID = options[0].UserValue(s).ID
+		}
+
+		user, err := queries.GetUser(db, discordID)
+		if err != nil {
+			return respondEphemeral(s, i, fmt.Sprintf("❌ <@%s> はユーザー登録されていません。", discordID))
+		}
+
+		ratings, err := queries.GetServerVirtualRatings(db, i.GuildID)
+		if err != nil {
+			return err
+		}
+		rating, rank := virtual.InitialRating, 0
+		contests := 0
+		for idx, r := range ratings {
+			if r.UserID == discordID {
+				rating, rank, contests = r.Rating, idx+1, r.Contests
+				break
+			}
+		}
+
+		history, err := queries.GetUserVirtualRatingHistory(db, i.GuildID, discordID, ratingHistoryLimit)
+		if err != nil {
+			return err
+		}
+
+		embed := &discordgo.MessageEmbed{
+			Title: fmt.Sprintf("📊 %s のバーチャルレーティング", user.AtCoderUsername),
+			Color: 0x3498db,
+		}
+		if rank == 0 {
+			embed.Description = fmt.Sprintf("まだレーティング対象のバーチャルコンテストに参加していません（初期値 %d）。", virtual.InitialRating)
+			return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+				Type: discordgo.InteractionResponseChannelMessageWithSource,
+				Data: &discordgo.InteractionResponseData{
+					Embeds: []*discordgo.MessageEmbed{embed},
+				},
+			})
+		}
+
+		embed.Fields = []*discordgo.MessageEmbedField{
+			{Name: "レーティング", Value: fmt.Sprintf("%d", rating), Inline: true},
+			{Name: "サーバー内順位", Value: fmt.Sprintf("%d / %d位", rank, len(ratings)), Inline: true},
+			{Name: "参加回数", Value: fmt.Sprintf("%d回", contests), Inline: true},
+		}
+
+		var sb strings.Builder
+		for _, change := range history {
+			sb.WriteString(fmt.Sprintf("%s %s（%d位）: %d → %d（%s）\n",
+				change.CreatedAt.In(virtual.JST).Format("01/02"), change.ContestTitle, change.ContestRank,
+				change.OldRating, change.NewRating, virtual.FormatRatingDelta(change.NewRating-change.OldRating)))
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("直近%d回の履歴", len(history)),
+			Value: sb.String(),
+		})
+
+		return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+			Type: discordgo.InteractionResponseChannelMessageWithSource,
+			Data: &discordgo.InteractionResponseData{
+				Embeds: []*discordgo.MessageEmbed{embed},
+			},
+		})
+	}
+}
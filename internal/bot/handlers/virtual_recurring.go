@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// HandleVirtualRecurring handles the /virtual-recurring command and its subcommands
+func HandleVirtualRecurring(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		sub := i.ApplicationCommandData().Options[0]
+		switch sub.Name {
+		case "create":
+			return handleVirtualRecurringCreate(db, s, i, sub.Options)
+		case "list":
+			return handleVirtualRecurringList(db, s, i)
+		case "delete":
+			return handleVirtualRecurringDelete(db, s, i, int(sub.Options[0].IntValue()))
+		default:
+			return fmt.Errorf("unknown virtual-recurring subcommand: %s", sub.Name)
+		}
+	}
+}
+
+// handleVirtualRecurringCreate handles /virtual-recurring create. Only server admins may create templates.
+func handleVirtualRecurringCreate(db *database.DB, s *discordgo.Session, i *discordgo.InteractionCreate, options []*discordgo.ApplicationCommandInteractionDataOption) error {
+	if !isServerAdmin(i) {
+		return respondEphemeral(s, i, "❌ 定期バーチャルは管理者のみ登録できます。")
+	}
+
+	template := &models.VirtualContestTemplate{
+		ServerID:        i.GuildID,
+		ChannelID:       i.ChannelID,
+		CreatedBy:       sql.NullString{String: i.Member.User.ID, Valid: true},
+		PenaltyRule:     models.PenaltyRuleAtCoder,
+		AnnounceMinutes: 60,
+	}
+	var problemsStr, rangeStr string
+	count := 0
+
+	for _, opt := range options {
+		switch opt.Name {
+		case "title":
+			template.Title = opt.StringValue()
+		case "schedule":
+			template.Schedule = strings.TrimSpace(opt.StringValue())
+		case "duration":
+			template.DurationMinutes = int(opt.IntValue())
+		case "problems":
+			problemsStr = opt.StringValue()
+		case "count":
+			count = int(opt.IntValue())
+		case "range":
+			rangeStr = opt.StringValue()
+		case "rule":
+			template.PenaltyRule = opt.StringValue()
+		case "announce":
+			template.AnnounceMinutes = int(opt.IntValue())
+		}
+	}
+
+	next, err := virtual.NextOccurrence(template, time.Now())
+	if err != nil {
+		return respondEphemeral(s, i, "❌ スケジュールはcron形式（分 時 日 月 曜日、JST）で、1日以上の間隔になるよう指定してください。例: 毎週土曜21時 → `0 21 * * 6`")
+	}
+
+	switch {
+	case problemsStr != "":
+		problemIDs, errMsg, err := resolveProblemInput(db, problemsStr)
+		if err != nil {
+			return err
+		}
+		if errMsg != "" {
+			return respondEphemeral(s, i, errMsg)
+		}
+		template.ProblemIDs = problemIDs
+	case rangeStr != "" && count > 0:
+		if count > 26 {
+			return respondEphemeral(s, i, "❌ 問題数は1〜26で指定してください。")
+		}
+		minDiff, maxDiff, err := virtual.ParseDifficultyRange(rangeStr)
+		if err != nil {
+			return respondEphemeral(s, i, "❌ 難易度の範囲は `400-1600` の形式で指定してください。")
+		}
+		template.ProblemCount = sql.NullInt64{Int64: int64(count), Valid: true}
+		template.DifficultyMin = sql.NullInt64{Int64: int64(minDiff), Valid: true}
+		template.DifficultyMax = sql.NullInt64{Int64: int64(maxDiff), Valid: true}
+	default:
+		return respondEphemeral(s, i, "❌ `problems` か、`count` と `range` の両方を指定してください。")
+	}
+
+	templateID, err := queries.CreateVirtualContestTemplate(db, template)
+	if err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("✅ 定期バーチャル「%s」を登録しました（ID: %d）。\n"+
+		"スケジュール: `%s`（JST）\n"+
+		"時間: %d分\n"+
+		"問題: %s\n"+
+		"次回: %s（%d分前にお知らせ）",
+		template.Title, templateID, template.Schedule, template.DurationMinutes,
+		virtual.DescribeTemplateProblems(template), next.In(virtual.JST).Format(virtual.StartTimeLayout), template.AnnounceMinutes)
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: message,
+		},
+	})
+}
+
+// handleVirtualRecurringList handles /virtual-recurring list
+func handleVirtualRecurringList(db *database.DB, s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	templates, err := queries.GetServerVirtualContestTemplates(db, i.GuildID)
+	if err != nil {
+		return err
+	}
+	if len(templates) == 0 {
+		return respondEphemeral(s, i, "定期バーチャルは登録されていません。`/virtual-recurring create` で登録できます。")
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: "📅 定期バーチャル一覧",
+		Color: 0x3498db,
+	}
+	now := time.Now()
+	for _, template := range templates {
+		nextStr := "-"
+		if next, err := virtual.NextOccurrence(template, now); err == nil {
+			nextStr = next.In(virtual.JST).Format(virtual.StartTimeLayout)
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name: fmt.Sprintf("#%d %s", template.ID, template.Title),
+			Value: fmt.Sprintf("`%s` ・ %d分 ・ %s\n<#%s> ・ 次回: %s",
+				template.Schedule, template.DurationMinutes, virtual.DescribeTemplateProblems(template),
+				template.ChannelID, nextStr),
+		})
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+}
+
+// handleVirtualRecurringDelete handles /virtual-recurring delete.
+// Only the creator or a server admin may delete a template; contests already created are kept.
+func handleVirtualRecurringDelete(db *database.DB, s *discordgo.Session, i *discordgo.InteractionCreate, templateID int) error {
+	templates, err := queries.GetServerVirtualContestTemplates(db, i.GuildID)
+	if err != nil {
+		return err
+	}
+
+	var template *models.VirtualContestTemplate
+	for _, t := range templates {
+		if t.ID == templateID {
+			template = t
+		}
+	}
+	if template == nil {
+		return respondEphemeral(s, i, "❌ 指定された定期バーチャルが見つかりませんでした。")
+	}
+
+	if !canManageVirtualTemplate(i, template) {
+		return respondEphemeral(s, i, "❌ 登録者または管理者のみ削除できます。")
+	}
+
+	if _, err := queries.DisableVirtualContestTemplate(db, template.ID, i.GuildID); err != nil {
+		return err
+	}
+	return respondEphemeral(s, i, fmt.Sprintf("✅ 定期バーチャル #%d「%s」を削除しました。作成済みのコンテストはそのまま残ります。", template.ID, template.Title))
+}
+
+// canManageVirtualTemplate checks whether the member may manage a template:
+// its creator or a server admin
+func canManageVirtualTemplate(i *discordgo.InteractionCreate, template *models.VirtualContestTemplate) bool {
+	if template.CreatedBy.Valid && template.CreatedBy.String == i.Member.User.ID {
+		return true
+	}
+	return isServerAdmin(i)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// HandleVirtualTeam handles the /virtual-team command and its subcommands
+func HandleVirtualTeam(db *database.DB) func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+	return func(s *discordgo.Session, i *discordgo.InteractionCreate) error {
+		sub := i.ApplicationCommandData().Options[0]
+
+		var contestID int
+		var teamName string
+		var target *discordgo.User
+		for _, opt := range sub.Options {
+			switch opt.Name {
+			case "contest-id":
+				contestID = int(opt.IntValue())
+			case "name":
+				teamName = strings.TrimSpace(opt.StringValue())
+			case "user":
+				target = opt.UserValue(s)
+			}
+		}
+
+		contest, err := queries.GetVirtualContest(db, contestID)
+		if err != nil || contest.ServerID != i.GuildID {
+			return respondEphemeral(s, i, "❌ このサーバーに該当するコンテストがありません。")
+		}
+
+		switch sub.Name {
+		case "create":
+			return handleVirtualTeamCreate(db, s, i, contest, teamName)
+		case "join":
+			return handleVirtualTeamJoin(db, s, i, contest, teamName, i.Member.User)
+		case "add":
+			if !canManageVirtualContest(i, contest) {
+				return respondEphemeral(s, i, "❌ コンテストの作成者または管理者のみメンバーを追加できます。")
+			}
+			return handleVirtualTeamJoin(db, s, i, contest, teamName, target)
+		case "leave":
+			left, err := queries.RemoveVirtualContestTeamMember(db, contest.ID, i.Member.User.ID)
+			if err != nil {
+				return err
+			}
+			if !left {
+				return respondEphemeral(s, i, "このコンテストではチームに所属していません。")
+			}
+			if err := respondEphemeral(s, i, "チームから抜けました。コンテストには個人として参加したままです。"); err != nil {
+				return err
+			}
+			return refreshLiveStandings(s, db, contest)
+		case "list":
+			return handleVirtualTeamList(db, s, i, contest)
+		default:
+			return fmt.Errorf("unknown virtual-team subcommand: %s", sub.Name)
+		}
+	}
+}
+
+// handleVirtualTeamCreate handles /virtual-team create; the creator joins the new team
+func handleVirtualTeamCreate(db *database.DB, s *discordgo.Session, i *discordgo.InteractionCreate, contest *models.VirtualContest, teamName string) error {
+	if teamName == "" {
+		return respondEphemeral(s, i, "❌ チーム名を指定してください。")
+	}
+
+	_, err := queries.CreateVirtualContestTeam(db, contest.ID, teamName)
+	if err == sql.ErrNoRows {
+		return respondEphemeral(s, i, fmt.Sprintf("❌ チーム「%s」はすでに存在します。", teamName))
+	}
+	if err != nil {
+		return err
+	}
+
+	return handleVirtualTeamJoin(db, s, i, contest, teamName, i.Member.User)
+}
+
+// handleVirtualTeamJoin puts a user in a team, joining them to the contest if needed
+func handleVirtualTeamJoin(db *database.DB, s *discordgo.Session, i *discordgo.InteractionCreate, contest *models.VirtualContest, teamName string, user *discordgo.User) error {
+	if user == nil {
+		return respondEphemeral(s, i, "❌ 追加するユーザーを指定してください。")
+	}
+	registered, err := queries.GetUser(db, user.ID)
+	if err != nil {
+		return respondEphemeral(s, i, fmt.Sprintf("❌ %s はユーザー登録されていません。", user.Username))
+	}
+	if !registered.Verified {
+		return respondEphemeral(s, i, fmt.Sprintf("❌ %s はAtCoderアカウントの本人確認が済んでいません。", user.Username))
+	}
+
+	team, err := queries.GetVirtualContestTeamByName(db, contest.ID, teamName)
+	if err != nil {
+		return respondEphemeral(s, i, fmt.Sprintf("❌ チーム「%s」が見つかりません。", teamName))
+	}
+
+	offset, errMsg := joinStartOffset(contest)
+	if errMsg != "" {
+		return respondEphemeral(s, i, errMsg)
+	}
+	if _, err := queries.JoinVirtualContest(db, contest.ID, user.ID, offset); err != nil {
+		return err
+	}
+	if err := queries.SetVirtualContestTeamMember(db, contest.ID, team.ID, user.ID); err != nil {
+		return err
+	}
+
+	warning, err := presolvedWarning(db, contest, user.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Content: fmt.Sprintf("👥 <@%s> がチーム「%s」に参加しました（%s）。", user.ID, team.Name, contest.Title) + warning,
+		},
+	}); err != nil {
+		return err
+	}
+	return refreshLiveStandings(s, db, contest)
+}
+
+// handleVirtualTeamList handles /virtual-team list
+func handleVirtualTeamList(db *database.DB, s *discordgo.Session, i *discordgo.InteractionCreate, contest *models.VirtualContest) error {
+	teams, err := queries.GetVirtualContestTeams(db, contest.ID)
+	if err != nil {
+		return err
+	}
+	if len(teams) == 0 {
+		return respondEphemeral(s, i, "このコンテストにはチームがありません。`/virtual-team create` で作成できます。")
+	}
+
+	members, err := queries.GetVirtualContestTeamMembers(db, contest.ID)
+	if err != nil {
+		return err
+	}
+	byTeam := make(map[int][]string)
+	for _, m := range members {
+		byTeam[m.TeamID] = append(byTeam[m.TeamID], m.AtCoderUsername)
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("👥 %s - チーム一覧", contest.Title),
+		Color: 0x3498db,
+	}
+	for _, team := range teams {
+		value := "（メンバーなし）"
+		if names := byTeam[team.ID]; len(names) > 0 {
+			value = strings.Join(names, ", ")
+		}
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  team.Name,
+			Value: value,
+		})
+	}
+
+	return s.InteractionRespond(i.Interaction, &discordgo.InteractionResponse{
+		Type: discordgo.InteractionResponseChannelMessageWithSource,
+		Data: &discordgo.InteractionResponseData{
+			Embeds: []*discordgo.MessageEmbed{embed},
+		},
+	})
+}
@@ -0,0 +1,67 @@
+package queries
+
+import (
+	"time"
+
+	"coding-winner/internal/models"
+)
+
+// GetSubmissionBackfill retrieves the backfill state of a user
+func GetSubmissionBackfill(db UserDB, userID string) (*models.SubmissionBackfill, error) {
+	var backfill models.SubmissionBackfill
+	query := `SELECT * FROM submission_backfills WHERE user_id = $1`
+	err := db.Get(&backfill, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &backfill, nil
+}
+
+// StartSubmissionBackfill creates the backfill state of a user. A backfill for a different
+// AtCoder username, after the user re-registered, starts over from the beginning.
+func StartSubmissionBackfill(db UserDB, userID, atcoderUsername string) error {
+	query := `
+		INSERT INTO submission_backfills (user_id, atcoder_username)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE
+		SET atcoder_username = EXCLUDED.atcoder_username,
+		    from_second = 0,
+		    fetched = 0,
+		    completed_at = NULL,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE submission_backfills.atcoder_username <> EXCLUDED.atcoder_username
+	`
+	_, err := db.Exec(query, userID, atcoderUsername)
+	return err
+}
+
+// SaveSubmissionBackfillCursor saves the progress of a backfill after a page
+func SaveSubmissionBackfillCursor(db UserDB, userID string, fromSecond int64, fetched int) error {
+	query := `
+		UPDATE submission_backfills
+		SET from_second = $2, fetched = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+	`
+	_, err := db.Exec(query, userID, fromSecond, fetched)
+	return err
+}
+
+// CompleteSubmissionBackfill marks a backfill as finished
+func CompleteSubmissionBackfill(db UserDB, userID string, completedAt time.Time) error {
+	query := `UPDATE submission_backfills SET completed_at = $2, updated_at = CURRENT_TIMESTAMP WHERE user_id = $1`
+	_, err := db.Exec(query, userID, completedAt)
+	return err
+}
+
+// GetUsersWithPendingBackfill retrieves users whose backfill has not finished or never started
+func GetUsersWithPendingBackfill(db UserDB) ([]*models.User, error) {
+	var users []*models.User
+	query := `
+		SELECT u.* FROM users u
+		LEFT JOIN submission_backfills b ON b.user_id = u.discord_id
+		WHERE b.completed_at IS NULL OR b.atcoder_username <> u.atcoder_username
+		ORDER BY u.created_at
+	`
+	err := db.Select(&users, query)
+	return users, err
+}
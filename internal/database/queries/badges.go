@@ -0,0 +1,155 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"coding-winner/internal/models"
+)
+
+// UnlockBadge records a badge for a user. It returns false if the badge was already unlocked.
+func UnlockBadge(db UserDB, userID, badgeKey string, unlockedAt time.Time) (bool, error) {
+	query := `
+		INSERT INTO user_badges (user_id, badge_key, unlocked_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id, badge_key) DO NOTHING
+	`
+	result, err := db.Exec(query, userID, badgeKey, unlockedAt)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// GetUserBadges retrieves all badges unlocked by a user
+func GetUserBadges(db UserDB, userID string) ([]*models.UserBadge, error) {
+	var badges []*models.UserBadge
+	query := `SELECT * FROM user_badges WHERE user_id = $1 ORDER BY unlocked_at`
+	err := db.Select(&badges, query, userID)
+	return badges, err
+}
+
+// GetNthFirstACTime returns when a user solved their nth distinct problem,
+// or an invalid time if they have solved fewer than n problems
+func GetNthFirstACTime(db UserDB, userID string, n int) (sql.NullTime, error) {
+	var achievedAt sql.NullTime
+	query := `
+		SELECT (
+			SELECT first_ac FROM (
+				SELECT problem_id, MIN(submitted_at) as first_ac
+				FROM submissions
+				WHERE user_id = $1 AND result = 'AC'
+				GROUP BY problem_id
+			) f
+			ORDER BY first_ac
+			OFFSET $2 - 1 LIMIT 1
+		)
+	`
+	err := db.Get(&achievedAt, query, userID, n)
+	return achievedAt, err
+}
+
+// GetFirstDifficultyACTime returns when a user first solved a problem of at least the given
+// difficulty, or an invalid time if they never have
+func GetFirstDifficultyACTime(db UserDB, userID string, minDiff int) (sql.NullTime, error) {
+	var achievedAt sql.NullTime
+	query := `
+		SELECT MIN(s.submitted_at) FROM submissions s
+		JOIN problems p ON s.problem_id = p.problem_id
+		WHERE s.user_id = $1 AND s.result = 'AC' AND p.difficulty >= $2
+	`
+	err := db.Get(&achievedAt, query, userID, minDiff)
+	return achievedAt, err
+}
+
+// GetContestSetCompletedTime returns when a user first completed every listed task of some
+// contest whose ID starts with contestPrefix, with all first ACs falling inside the given
+// window, or an invalid time if they never have
+func GetContestSetCompletedTime(db UserDB, userID, contestPrefix string, letters []string, within time.Duration) (sql.NullTime, error) {
+	var achievedAt sql.NullTime
+	query := `
+		SELECT MIN(completed_at) FROM (
+			SELECT MAX(f.first_ac) as completed_at
+			FROM (
+				SELECT contest_id, problem_id, MIN(submitted_at) as first_ac
+				FROM submissions
+				WHERE user_id = $1
+					AND result = 'AC'
+					AND contest_id LIKE $2 || '%'
+					AND problem_id IN (
+						SELECT contest_id || '_' || letter FROM unnest($3::text[]) as letter
+					)
+				GROUP BY contest_id, problem_id
+			) f
+			GROUP BY f.contest_id
+			HAVING COUNT(*) = cardinality($3::text[])
+				AND MAX(f.first_ac) - MIN(f.first_ac) < $4 * INTERVAL '1 second'
+		) c
+	`
+	err := db.Get(&achievedAt, query, userID, contestPrefix, pq.Array(letters), within.Seconds())
+	return achievedAt, err
+}
+
+// SaveBadgeConfig saves badge announcement configuration
+func SaveBadgeConfig(db UserDB, config *models.BadgeConfig) error {
+	query := `
+		INSERT INTO badge_config (server_id, channel_id, enabled)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (server_id) DO UPDATE
+		SET channel_id = EXCLUDED.channel_id,
+		    enabled = EXCLUDED.enabled
+	`
+	_, err := db.Exec(query, config.ServerID, config.ChannelID, config.Enabled)
+	return err
+}
+
+// GetAllEnabledBadgeConfigs retrieves all enabled badge announcement configs
+func GetAllEnabledBadgeConfigs(db UserDB) ([]*models.BadgeConfig, error) {
+	var configs []*models.BadgeConfig
+	query := `SELECT * FROM badge_config WHERE enabled = true`
+	err := db.Select(&configs, query)
+	return configs, err
+}
+
+// GetStreakReachedTime returns the first new AC on the day a user's run of consecutive days (in JST)
+// with at least one new AC first reached the given length, or an invalid time if it never has
+func GetStreakReachedTime(db UserDB, userID string, days int) (sql.NullTime, error) {
+	query := `
+		SELECT DATE(first_ac AT TIME ZONE 'Asia/Tokyo') as day, MIN(first_ac) as first_ac
+		FROM (
+			SELECT problem_id, MIN(submitted_at) as first_ac
+			FROM submissions
+			WHERE user_id = $1 AND result = 'AC'
+			GROUP BY problem_id
+		) f
+		GROUP BY day
+		ORDER BY day
+	`
+	type Result struct {
+		Day     time.Time `db:"day"`
+		FirstAC time.Time `db:"first_ac"`
+	}
+
+	var results []Result
+	if err := db.Select(&results, query, userID); err != nil {
+		return sql.NullTime{}, err
+	}
+
+	current := 0
+	for i, r := range results {
+		if i > 0 && results[i-1].Day.AddDate(0, 0, 1).Equal(r.Day) {
+			current++
+		} else {
+			current = 1
+		}
+		if current >= days {
+			return sql.NullTime{Time: r.FirstAC, Valid: true}, nil
+		}
+	}
+	return sql.NullTime{}, nil
+}
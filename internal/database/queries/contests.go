@@ -0,0 +1,90 @@
+package queries
+
+import (
+	"github.com/lib/pq"
+	"coding-winner/internal/models"
+)
+
+// UpsertContests bulk upserts AtCoder contests
+func UpsertContests(db UserDB, contests []*models.Contest) error {
+	query := `
+		INSERT INTO contests (contest_id, title, start_time, duration_seconds, rate_change)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (contest_id) DO UPDATE
+		SET title = EXCLUDED.title,
+		    start_time = EXCLUDED.start_time,
+		    duration_seconds = EXCLUDED.duration_seconds,
+		    rate_change = EXCLUDED.rate_change
+	`
+
+	for _, c := range contests {
+		_, err := db.Exec(query, c.ContestID, c.Title, c.StartTime, c.DurationSeconds, c.RateChange)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpsertContestProblems bulk upserts the problem sets of AtCoder contests
+func UpsertContestProblems(db UserDB, contestProblems []*models.ContestProblem) error {
+	query := `
+		INSERT INTO contest_problems (contest_id, problem_id, problem_index, point)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (contest_id, problem_id) DO UPDATE
+		SET problem_index = EXCLUDED.problem_index,
+		    point = EXCLUDED.point
+	`
+
+	for _, cp := range contestProblems {
+		_, err := db.Exec(query, cp.ContestID, cp.ProblemID, cp.ProblemIndex, cp.Point)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetContest retrieves an AtCoder contest by ID
+func GetContest(db UserDB, contestID string) (*models.Contest, error) {
+	var contest models.Contest
+	query := `SELECT * FROM contests WHERE contest_id = $1`
+	err := db.Get(&contest, query, contestID)
+	if err != nil {
+		return nil, err
+	}
+	return &contest, nil
+}
+
+// GetContestProblemSet retrieves the problems of an AtCoder contest in task order
+func GetContestProblemSet(db UserDB, contestID string) ([]*models.ContestProblem, error) {
+	var problems []*models.ContestProblem
+	query := `
+		SELECT * FROM contest_problems
+		WHERE contest_id = $1
+		ORDER BY LENGTH(problem_index), problem_index
+	`
+	err := db.Select(&problems, query, contestID)
+	return problems, err
+}
+
+// GetUnsolvedContests retrieves up to limit random finished contests whose ID starts with the
+// prefix and none of whose problems has been solved by any of the users
+func GetUnsolvedContests(db UserDB, userIDs []string, prefix string, limit int) ([]*models.Contest, error) {
+	var contests []*models.Contest
+	query := `
+		SELECT c.* FROM contests c
+		WHERE c.contest_id LIKE $2 || '%'
+			AND c.start_time + c.duration_seconds * INTERVAL '1 second' < NOW()
+			AND EXISTS (SELECT 1 FROM contest_problems cp WHERE cp.contest_id = c.contest_id)
+			AND NOT EXISTS (
+				SELECT 1 FROM contest_problems cp
+				JOIN submissions s ON s.problem_id = cp.problem_id
+				WHERE cp.contest_id = c.contest_id AND s.user_id = ANY($1) AND s.result = 'AC'
+			)
+		ORDER BY RANDOM()
+		LIMIT $3
+	`
+	err := db.Select(&contests, query, pq.Array(userIDs), prefix, limit)
+	return contests, err
+}
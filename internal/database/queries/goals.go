@@ -0,0 +1,83 @@
+package queries
+
+import (
+	"fmt"
+	"time"
+
+	"coding-winner/internal/models"
+)
+
+// CreateGoal creates a new goal
+func CreateGoal(db UserDB, goal *models.Goal) (int, error) {
+	query := `
+		INSERT INTO goals (user_id, server_id, channel_id, kind, target, color, period_start, period_end)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	var id int
+	err := db.Get(&id, query, goal.UserID, goal.ServerID, goal.ChannelID, goal.Kind,
+		goal.Target, goal.Color, goal.PeriodStart, goal.PeriodEnd)
+	return id, err
+}
+
+// GetUserGoals retrieves all goals of a user in a server
+func GetUserGoals(db UserDB, userID, serverID string) ([]*models.Goal, error) {
+	var goals []*models.Goal
+	query := `
+		SELECT * FROM goals
+		WHERE user_id = $1 AND server_id = $2
+		ORDER BY created_at DESC
+	`
+	err := db.Select(&goals, query, userID, serverID)
+	return goals, err
+}
+
+// GetActiveGoals retrieves all goals that are neither completed nor expired
+func GetActiveGoals(db UserDB, now time.Time) ([]*models.Goal, error) {
+	var goals []*models.Goal
+	query := `
+		SELECT * FROM goals
+		WHERE completed_at IS NULL
+			AND (period_end IS NULL OR period_end > $1)
+	`
+	err := db.Select(&goals, query, now)
+	return goals, err
+}
+
+// CompleteGoal marks a goal as completed
+func CompleteGoal(db UserDB, goalID int, completedAt time.Time) error {
+	query := `UPDATE goals SET completed_at = $2 WHERE id = $1 AND completed_at IS NULL`
+	_, err := db.Exec(query, goalID, completedAt)
+	return err
+}
+
+// GetCompletedGoals retrieves goals in a server completed within [startTime, endTime)
+func GetCompletedGoals(db UserDB, serverID string, startTime, endTime time.Time) ([]*models.Goal, error) {
+	var goals []*models.Goal
+	query := `
+		SELECT * FROM goals
+		WHERE server_id = $1
+			AND completed_at >= $2
+			AND completed_at < $3
+		ORDER BY completed_at
+	`
+	err := db.Select(&goals, query, serverID, startTime, endTime)
+	return goals, err
+}
+
+// DeleteGoal deletes a goal owned by the user
+func DeleteGoal(db UserDB, goalID int, userID string) error {
+	query := `DELETE FROM goals WHERE id = $1 AND user_id = $2`
+	result, err := db.Exec(query, goalID, userID)
+	if err != nil {
+		return err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return fmt.Errorf("goal not found")
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package queries
 import (
 	"database/sql"
 
+	"github.com/lib/pq"
 	"coding-winner/internal/models"
 )
 
@@ -13,16 +14,20 @@ func UpsertProblems(db UserDB, problems []*models.Problem) error {
 	}
 
 	query := `
-		INSERT INTO problems (problem_id, contest_id, title, difficulty)
-		VALUES ($1, $2, $3, $4)
+		INSERT INTO problems (problem_id, contest_id, title, difficulty, slope, intercept, discrimination)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (problem_id) DO UPDATE
 		SET contest_id = EXCLUDED.contest_id,
 		    title = EXCLUDED.title,
-		    difficulty = EXCLUDED.difficulty
+		    difficulty = EXCLUDED.difficulty,
+		    slope = EXCLUDED.slope,
+		    intercept = EXCLUDED.intercept,
+		    discrimination = EXCLUDED.discrimination
 	`
 
 	for _, p := range problems {
-		_, err := db.Exec(query, p.ProblemID, p.ContestID, p.Title, p.Difficulty)
+		_, err := db.Exec(query, p.ProblemID, p.ContestID, p.Title, p.Difficulty,
+			p.Slope, p.Intercept, p.Discrimination)
 		if err != nil {
 			return err
 		}
@@ -41,6 +46,35 @@ func GetProblem(db UserDB, problemID string) (*models.Problem, error) {
 	return &problem, nil
 }
 
+// GetProblemsByIDs retrieves the problems with the given IDs (in no particular order)
+func GetProblemsByIDs(db UserDB, problemIDs []string) ([]*models.Problem, error) {
+	var problems []*models.Problem
+	query := `SELECT * FROM problems WHERE problem_id = ANY($1)`
+	err := db.Select(&problems, query, pq.Array(problemIDs))
+	return problems, err
+}
+
+// GetContestProblems retrieves all problems of an AtCoder contest ordered by title
+func GetContestProblems(db UserDB, contestID string) ([]*models.Problem, error) {
+	var problems []*models.Problem
+	query := `SELECT * FROM problems WHERE contest_id = $1 ORDER BY title, problem_id`
+	err := db.Select(&problems, query, contestID)
+	return problems, err
+}
+
+// SuggestProblemIDs retrieves up to limit problem IDs starting with the given prefix
+func SuggestProblemIDs(db UserDB, prefix string, limit int) ([]string, error) {
+	var ids []string
+	query := `
+		SELECT problem_id FROM problems
+		WHERE problem_id LIKE $1 || '%'
+		ORDER BY problem_id
+		LIMIT $2
+	`
+	err := db.Select(&ids, query, prefix, limit)
+	return ids, err
+}
+
 // GetRandomProblemByDifficulty gets a random problem within difficulty range
 func GetRandomProblemByDifficulty(db UserDB, minDiff, maxDiff int) (*models.Problem, error) {
 	var problem models.Problem
@@ -57,6 +91,53 @@ func GetRandomProblemByDifficulty(db UserDB, minDiff, maxDiff int) (*models.Prob
 	return &problem, nil
 }
 
+// GetUnsolvedModeledProblems retrieves problems the user has not solved that have an IRT model
+func GetUnsolvedModeledProblems(db UserDB, userID string) ([]*models.Problem, error) {
+	var problems []*models.Problem
+	query := `
+		SELECT p.* FROM problems p
+		WHERE p.difficulty IS NOT NULL
+			AND p.discrimination IS NOT NULL
+			AND NOT EXISTS (
+				SELECT 1 FROM submissions s
+				WHERE s.problem_id = p.problem_id AND s.user_id = $1 AND s.result = 'AC'
+			)
+	`
+	err := db.Select(&problems, query, userID)
+	return problems, err
+}
+
+// GetProblemsUnsolvedByAll retrieves problems within the difficulty range that none of the users has solved
+func GetProblemsUnsolvedByAll(db UserDB, userIDs []string, minDiff, maxDiff int) ([]*models.Problem, error) {
+	var problems []*models.Problem
+	query := `
+		SELECT p.* FROM problems p
+		WHERE p.difficulty >= $2 AND p.difficulty <= $3
+			AND NOT EXISTS (
+				SELECT 1 FROM submissions s
+				WHERE s.problem_id = p.problem_id AND s.user_id = ANY($1) AND s.result = 'AC'
+			)
+	`
+	err := db.Select(&problems, query, pq.Array(userIDs), minDiff, maxDiff)
+	return problems, err
+}
+
+// GetUserProblemAttempts retrieves every modeled problem the user has submitted to
+func GetUserProblemAttempts(db UserDB, userID string) ([]*models.ProblemAttempt, error) {
+	var attempts []*models.ProblemAttempt
+	query := `
+		SELECT p.*, BOOL_OR(s.result = 'AC') as solved
+		FROM submissions s
+		JOIN problems p ON s.problem_id = p.problem_id
+		WHERE s.user_id = $1
+			AND p.difficulty IS NOT NULL
+			AND p.discrimination IS NOT NULL
+		GROUP BY p.problem_id
+	`
+	err := db.Select(&attempts, query, userID)
+	return attempts, err
+}
+
 // GetProblemsCount returns the total number of problems
 func GetProblemsCount(db UserDB) (int, error) {
 	var count int
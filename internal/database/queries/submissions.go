@@ -1,31 +1,69 @@
 package queries
 
 import (
+	"database/sql"
+	"fmt"
+	"sort"
 	"time"
 
+	"github.com/lib/pq"
 	"coding-winner/internal/models"
 )
 
-// CreateSubmissions bulk inserts submissions
-func CreateSubmissions(db UserDB, submissions []*models.Submission) error {
+// CreateSubmissions bulk upserts submissions. Known submissions get their result and point
+// updated, so that submissions first synced while being judged, and rejudged ones, stay
+// current. It returns the known submissions whose result or point changed.
+func CreateSubmissions(db UserDB, submissions []*models.Submission) ([]*models.SubmissionResultChange, error) {
 	if len(submissions) == 0 {
-		return nil
+		return nil, nil
 	}
 
+	// The CTE sees the row as it was before the upsert; a new submission has no old result
 	query := `
+		WITH old AS (SELECT result FROM submissions WHERE id = $1)
 		INSERT INTO submissions (id, user_id, problem_id, contest_id, result, point, language, submitted_at)
 		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		ON CONFLICT (id) DO NOTHING
+		ON CONFLICT (id) DO UPDATE
+		SET result = EXCLUDED.result,
+		    point = EXCLUDED.point,
+		    synced_at = CURRENT_TIMESTAMP
+		WHERE submissions.result IS DISTINCT FROM EXCLUDED.result
+		   OR submissions.point IS DISTINCT FROM EXCLUDED.point
+		RETURNING (SELECT result FROM old) AS old_result
 	`
 
+	var changes []*models.SubmissionResultChange
 	for _, sub := range submissions {
-		_, err := db.Exec(query, sub.ID, sub.UserID, sub.ProblemID, sub.ContestID,
+		var oldResults []sql.NullString
+		err := db.Select(&oldResults, query, sub.ID, sub.UserID, sub.ProblemID, sub.ContestID,
 			sub.Result, sub.Point, sub.Language, sub.SubmittedAt)
 		if err != nil {
-			return err
+			return nil, err
+		}
+		if len(oldResults) > 0 && oldResults[0].Valid {
+			changes = append(changes, &models.SubmissionResultChange{
+				Submission: sub,
+				OldResult:  oldResults[0].String,
+			})
 		}
 	}
-	return nil
+	return changes, nil
+}
+
+// GetUsersWithPendingSubmissions retrieves users who have submissions since the given time
+// whose result is not one of the final results, with the oldest such submission
+func GetUsersWithPendingSubmissions(db UserDB, finalResults []string, since time.Time) ([]*models.PendingSubmissionUser, error) {
+	var users []*models.PendingSubmissionUser
+	query := `
+		SELECT s.user_id, u.atcoder_username, MIN(s.submitted_at) as oldest_pending
+		FROM submissions s
+		JOIN users u ON s.user_id = u.discord_id
+		WHERE s.submitted_at >= $2
+			AND (s.result IS NULL OR s.result <> ALL($1))
+		GROUP BY s.user_id, u.atcoder_username
+	`
+	err := db.Select(&users, query, pq.Array(finalResults), since)
+	return users, err
 }
 
 // GetUserSubmissions retrieves submissions for a user
@@ -53,6 +91,22 @@ func GetUserSubmissionsSince(db UserDB, userID string, since time.Time) ([]*mode
 	return submissions, err
 }
 
+// GetSubmissionsForProblems retrieves the given users' submissions to the given problems
+// within [startTime, endTime), oldest first
+func GetSubmissionsForProblems(db UserDB, userIDs, problemIDs []string, startTime, endTime time.Time) ([]*models.Submission, error) {
+	var submissions []*models.Submission
+	query := `
+		SELECT * FROM submissions
+		WHERE user_id = ANY($1)
+			AND problem_id = ANY($2)
+			AND submitted_at >= $3
+			AND submitted_at < $4
+		ORDER BY submitted_at, id
+	`
+	err := db.Select(&submissions, query, pq.Array(userIDs), pq.Array(problemIDs), startTime, endTime)
+	return submissions, err
+}
+
 // GetLatestSubmissionTime gets the latest submission time for a user
 func GetLatestSubmissionTime(db UserDB, userID string) (*time.Time, error) {
 	var t *time.Time
@@ -66,7 +120,32 @@ func GetLatestSubmissionTime(db UserDB, userID string) (*time.Time, error) {
 	return t, nil
 }
 
-// GetWeeklyACCount gets AC count for users in the past week grouped by difficulty
+// GetNewACCount counts problems first solved by a user within [startTime, endTime)
+func GetNewACCount(db UserDB, userID string, startTime, endTime time.Time) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM (` + firstACQuery + `) f
+		WHERE f.user_id = $1 AND f.first_ac >= $2 AND f.first_ac < $3
+	`
+	err := db.Get(&count, query, userID, startTime, endTime)
+	return count, err
+}
+
+// GetNewACCountInDifficultyRange counts problems with difficulty in [minDiff, maxDiff)
+// first solved by a user within [startTime, endTime)
+func GetNewACCountInDifficultyRange(db UserDB, userID string, startTime, endTime time.Time, minDiff, maxDiff int) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*) FROM (` + firstACQuery + `) f
+		JOIN problems p ON f.problem_id = p.problem_id
+		WHERE f.user_id = $1 AND f.first_ac >= $2 AND f.first_ac < $3
+			AND p.difficulty >= $4 AND p.difficulty < $5
+	`
+	err := db.Get(&count, query, userID, startTime, endTime, minDiff, maxDiff)
+	return count, err
+}
+
+// GetWeeklyACCount gets AC count for verified users in the past week grouped by difficulty
 func GetWeeklyACCount(db UserDB, startTime, endTime time.Time) ([]models.WeeklyStats, error) {
 	query := `
 		SELECT
@@ -76,6 +155,7 @@ func GetWeeklyACCount(db UserDB, startTime, endTime time.Time) ([]models.WeeklyS
 		FROM submissions s
 		JOIN users u ON s.user_id = u.discord_id
 		WHERE s.result = 'AC'
+			AND u.verified
 			AND s.submitted_at >= $1
 			AND s.submitted_at < $2
 		GROUP BY s.user_id, u.atcoder_username
@@ -136,15 +216,15 @@ func GetACCountByDifficulty(db UserDB, userID string, startTime, endTime time.Ti
 	diffMap := make(map[string]int)
 	for _, r := range results {
 		// Convert difficulty to color
-		color := difficultyToColor(r.Difficulty)
-		diffMap[color] = r.Count
+		color := DifficultyToColor(r.Difficulty)
+		diffMap[color] += r.Count
 	}
 
 	return diffMap, nil
 }
 
-// difficultyToColor converts difficulty rating to color name
-func difficultyToColor(diff int) string {
+// DifficultyToColor converts difficulty rating to color name
+func DifficultyToColor(diff int) string {
 	if diff < 400 {
 		return "灰色"
 	} else if diff < 800 {
@@ -163,3 +243,199 @@ func difficultyToColor(diff int) string {
 		return "赤色"
 	}
 }
+
+// Leaderboard metrics
+const (
+	MetricAC            = "ac"
+	MetricNewAC         = "new-ac"
+	MetricWeighted      = "weighted"
+	MetricStreak        = "streak"
+	MetricMaxDifficulty = "max-difficulty"
+)
+
+// firstACQuery selects the first AC time of each (user, problem) pair
+const firstACQuery = `
+	SELECT user_id, problem_id, MIN(submitted_at) as first_ac
+	FROM submissions
+	WHERE result = 'AC'
+	GROUP BY user_id, problem_id
+`
+
+// GetLeaderboard ranks users by the given metric within [startTime, endTime).
+// Users without any matching AC and users whose account is not verified are omitted.
+func GetLeaderboard(db UserDB, metric string, startTime, endTime time.Time) ([]models.LeaderboardEntry, error) {
+	var entries []models.LeaderboardEntry
+	var err error
+
+	switch metric {
+	case MetricAC:
+		var stats []models.WeeklyStats
+		stats, err = GetWeeklyACCount(db, startTime, endTime)
+		for _, stat := range stats {
+			entries = append(entries, models.LeaderboardEntry{
+				UserID:          stat.UserID,
+				AtCoderUsername: stat.AtCoderUsername,
+				Value:           stat.ACCount,
+			})
+		}
+	case MetricNewAC:
+		query := `
+			SELECT f.user_id, u.atcoder_username, COUNT(*) as value
+			FROM (` + firstACQuery + `) f
+			JOIN users u ON f.user_id = u.discord_id
+			WHERE u.verified AND f.first_ac >= $1 AND f.first_ac < $2
+			GROUP BY f.user_id, u.atcoder_username
+			ORDER BY value DESC
+		`
+		err = db.Select(&entries, query, startTime, endTime)
+	case MetricWeighted:
+		// Each newly solved problem is weighted by its color tier (gray=1 ... red=8)
+		query := `
+			SELECT f.user_id, u.atcoder_username,
+				SUM(LEAST(GREATEST(COALESCE(p.difficulty, 0), 0) / 400, 7) + 1) as value
+			FROM (` + firstACQuery + `) f
+			JOIN users u ON f.user_id = u.discord_id
+			LEFT JOIN problems p ON f.problem_id = p.problem_id
+			WHERE u.verified AND f.first_ac >= $1 AND f.first_ac < $2
+			GROUP BY f.user_id, u.atcoder_username
+			ORDER BY value DESC
+		`
+		err = db.Select(&entries, query, startTime, endTime)
+	case MetricStreak:
+		entries, err = getLongestStreaks(db, startTime, endTime)
+	case MetricMaxDifficulty:
+		query := `
+			SELECT s.user_id, u.atcoder_username, MAX(p.difficulty) as value
+			FROM submissions s
+			JOIN users u ON s.user_id = u.discord_id
+			JOIN problems p ON s.problem_id = p.problem_id
+			WHERE s.result = 'AC'
+				AND u.verified
+				AND p.difficulty IS NOT NULL
+				AND s.submitted_at >= $1
+				AND s.submitted_at < $2
+			GROUP BY s.user_id, u.atcoder_username
+			ORDER BY value DESC
+		`
+		err = db.Select(&entries, query, startTime, endTime)
+	default:
+		return nil, fmt.Errorf("unknown leaderboard metric: %s", metric)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+	return entries, nil
+}
+
+// getLongestStreaks computes each user's longest run of consecutive days (in JST)
+// with at least one new AC within [startTime, endTime)
+func getLongestStreaks(db UserDB, startTime, endTime time.Time) ([]models.LeaderboardEntry, error) {
+	query := `
+		SELECT DISTINCT f.user_id, u.atcoder_username, DATE(f.first_ac AT TIME ZONE 'Asia/Tokyo') as day
+		FROM (` + firstACQuery + `) f
+		JOIN users u ON f.user_id = u.discord_id
+		WHERE u.verified AND f.first_ac >= $1 AND f.first_ac < $2
+		ORDER BY f.user_id, day
+	`
+
+	type Result struct {
+		UserID          string    `db:"user_id"`
+		AtCoderUsername string    `db:"atcoder_username"`
+		Day             time.Time `db:"day"`
+	}
+
+	var results []Result
+	if err := db.Select(&results, query, startTime, endTime); err != nil {
+		return nil, err
+	}
+
+	var entries []models.LeaderboardEntry
+	current := 0
+	for i, r := range results {
+		if i > 0 && results[i-1].UserID == r.UserID && results[i-1].Day.AddDate(0, 0, 1).Equal(r.Day) {
+			current++
+		} else {
+			current = 1
+		}
+
+		if i == 0 || results[i-1].UserID != r.UserID {
+			entries = append(entries, models.LeaderboardEntry{
+				UserID:          r.UserID,
+				AtCoderUsername: r.AtCoderUsername,
+			})
+		}
+		last := &entries[len(entries)-1]
+		if current > last.Value {
+			last.Value = current
+		}
+	}
+
+	sort.SliceStable(entries, func(a, b int) bool {
+		return entries[a].Value > entries[b].Value
+	})
+	return entries, nil
+}
+
+// GetSolvedNotSolvedBy retrieves problems solved by userID but not by otherUserID
+func GetSolvedNotSolvedBy(db UserDB, userID, otherUserID string) ([]*models.Problem, error) {
+	var problems []*models.Problem
+	query := `
+		SELECT p.* FROM problems p
+		WHERE EXISTS (
+			SELECT 1 FROM submissions s
+			WHERE s.problem_id = p.problem_id AND s.user_id = $1 AND s.result = 'AC'
+		)
+		AND NOT EXISTS (
+			SELECT 1 FROM submissions s
+			WHERE s.problem_id = p.problem_id AND s.user_id = $2 AND s.result = 'AC'
+		)
+		ORDER BY p.difficulty DESC NULLS LAST
+	`
+	err := db.Select(&problems, query, userID, otherUserID)
+	return problems, err
+}
+
+// GetCommonContestResults compares two users' scores in contests both of them took part in.
+// Only submissions made during the contest count, and a user's score in a contest is the
+// sum of their best point on each problem.
+func GetCommonContestResults(db UserDB, userA, userB string) ([]models.ContestComparison, error) {
+	query := `
+		WITH best AS (
+			SELECT s.user_id, s.contest_id, s.problem_id,
+				MAX(s.point) as point,
+				BOOL_OR(s.result = 'AC') as solved,
+				MIN(s.submitted_at) as first_submitted
+			FROM submissions s
+			JOIN contests c ON c.contest_id = s.contest_id
+			WHERE s.user_id IN ($1, $2)
+				AND s.submitted_at >= c.start_time
+				AND s.submitted_at < c.start_time + c.duration_seconds * INTERVAL '1 second'
+			GROUP BY s.user_id, s.contest_id, s.problem_id
+		), per_contest AS (
+			SELECT user_id, contest_id,
+				COALESCE(SUM(point), 0) as score,
+				COUNT(*) FILTER (WHERE solved) as solved_count,
+				MIN(first_submitted) as started_at
+			FROM best
+			GROUP BY user_id, contest_id
+		)
+		SELECT
+			a.contest_id,
+			a.score as score_a,
+			b.score as score_b,
+			a.solved_count as solved_a,
+			b.solved_count as solved_b,
+			LEAST(a.started_at, b.started_at) as started_at
+		FROM per_contest a
+		JOIN per_contest b ON a.contest_id = b.contest_id
+		WHERE a.user_id = $1 AND b.user_id = $2
+		ORDER BY started_at DESC
+	`
+	var results []models.ContestComparison
+	err := db.Select(&results, query, userA, userB)
+	return results, err
+}
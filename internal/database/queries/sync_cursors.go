@@ -0,0 +1,46 @@
+package queries
+
+import (
+	"database/sql"
+	"time"
+)
+
+// GetSyncCursor retrieves the position of a named sync. It returns false if the sync never ran.
+func GetSyncCursor(db UserDB, name string) (int64, bool, error) {
+	var fromSecond int64
+	query := `SELECT from_second FROM sync_cursors WHERE name = $1`
+	err := db.Get(&fromSecond, query, name)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return fromSecond, true, nil
+}
+
+// SaveSyncCursor saves the position of a named sync
+func SaveSyncCursor(db UserDB, name string, fromSecond int64) error {
+	query := `
+		INSERT INTO sync_cursors (name, from_second)
+		VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE
+		SET from_second = EXCLUDED.from_second,
+		    updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := db.Exec(query, name, fromSecond)
+	return err
+}
+
+// GetLatestSubmissionTimeOverall retrieves the time of the newest synced submission of any user
+func GetLatestSubmissionTimeOverall(db UserDB) (*time.Time, error) {
+	var latest sql.NullTime
+	query := `SELECT MAX(submitted_at) FROM submissions`
+	if err := db.Get(&latest, query); err != nil {
+		return nil, err
+	}
+	if !latest.Valid {
+		return nil, nil
+	}
+	return &latest.Time, nil
+}
@@ -0,0 +1,94 @@
+package queries
+
+import (
+	"coding-winner/internal/models"
+)
+
+// GetUserSyncState retrieves the sync state of a user
+func GetUserSyncState(db UserDB, userID string) (*models.UserSyncState, error) {
+	var state models.UserSyncState
+	query := `SELECT * FROM user_sync_state WHERE user_id = $1`
+	err := db.Get(&state, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// RecordUserSyncSuccess saves a user's sync cursor after a successful sync and clears failures
+func RecordUserSyncSuccess(db UserDB, userID string, lastSyncedEpoch int64) error {
+	query := `
+		INSERT INTO user_sync_state (user_id, last_synced_epoch, last_success_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP)
+		ON CONFLICT (user_id) DO UPDATE
+		SET last_synced_epoch = EXCLUDED.last_synced_epoch,
+		    last_success_at = CURRENT_TIMESTAMP,
+		    consecutive_failures = 0,
+		    updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := db.Exec(query, userID, lastSyncedEpoch)
+	return err
+}
+
+// RecordUserSyncFailure records a failed sync of a user
+func RecordUserSyncFailure(db UserDB, userID, message string) error {
+	query := `
+		INSERT INTO user_sync_state (user_id, last_error, last_error_at, consecutive_failures)
+		VALUES ($1, $2, CURRENT_TIMESTAMP, 1)
+		ON CONFLICT (user_id) DO UPDATE
+		SET last_error = EXCLUDED.last_error,
+		    last_error_at = CURRENT_TIMESTAMP,
+		    consecutive_failures = user_sync_state.consecutive_failures + 1,
+		    updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := db.Exec(query, userID, message)
+	return err
+}
+
+// RecordAllUsersSyncSuccess marks every user whose backfill has completed as synced, after a
+// sync that covers everyone. Users still being backfilled keep their own failure state, and
+// the per-user cursors are left as they are.
+func RecordAllUsersSyncSuccess(db UserDB) error {
+	query := `
+		INSERT INTO user_sync_state (user_id, last_success_at)
+		SELECT u.discord_id, CURRENT_TIMESTAMP
+		FROM users u
+		JOIN submission_backfills b ON b.user_id = u.discord_id
+		WHERE b.atcoder_username = u.atcoder_username AND b.completed_at IS NOT NULL
+		ON CONFLICT (user_id) DO UPDATE
+		SET last_success_at = CURRENT_TIMESTAMP,
+		    consecutive_failures = 0,
+		    updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := db.Exec(query)
+	return err
+}
+
+// RecordAllUsersSyncFailure records a failed sync for every user, after a sync that covers everyone
+func RecordAllUsersSyncFailure(db UserDB, message string) error {
+	query := `
+		INSERT INTO user_sync_state (user_id, last_error, last_error_at, consecutive_failures)
+		SELECT discord_id, $1, CURRENT_TIMESTAMP, 1 FROM users
+		ON CONFLICT (user_id) DO UPDATE
+		SET last_error = EXCLUDED.last_error,
+		    last_error_at = CURRENT_TIMESTAMP,
+		    consecutive_failures = user_sync_state.consecutive_failures + 1,
+		    updated_at = CURRENT_TIMESTAMP
+	`
+	_, err := db.Exec(query, message)
+	return err
+}
+
+// GetFailingUserSyncStates retrieves users whose latest syncs failed, most failures first
+func GetFailingUserSyncStates(db UserDB) ([]*models.UserSyncStateRow, error) {
+	var states []*models.UserSyncStateRow
+	query := `
+		SELECT st.*, u.atcoder_username
+		FROM user_sync_state st
+		JOIN users u ON u.discord_id = st.user_id
+		WHERE st.consecutive_failures > 0
+		ORDER BY st.consecutive_failures DESC, st.last_error_at DESC
+	`
+	err := db.Select(&states, query)
+	return states, err
+}
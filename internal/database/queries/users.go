@@ -13,13 +13,15 @@ type UserDB interface {
 	Select(dest interface{}, query string, args ...interface{}) error
 }
 
-// CreateUser creates a new user
+// CreateUser creates a new user. Changing the AtCoder username of an existing
+// user clears its verification.
 func CreateUser(db UserDB, user *models.User) error {
 	query := `
 		INSERT INTO users (discord_id, atcoder_username)
 		VALUES ($1, $2)
 		ON CONFLICT (discord_id) DO UPDATE
 		SET atcoder_username = EXCLUDED.atcoder_username,
+		    verified = users.verified AND users.atcoder_username = EXCLUDED.atcoder_username,
 		    updated_at = CURRENT_TIMESTAMP
 	`
 	_, err := db.Exec(query, user.DiscordID, user.AtCoderUsername)
@@ -87,3 +89,54 @@ func UserExists(db UserDB, discordID string) (bool, error) {
 	err := db.Get(&exists, query, discordID)
 	return exists, err
 }
+
+// SaveUserVerification saves a member's pending claim of an AtCoder account, replacing any earlier claim
+func SaveUserVerification(db UserDB, verification *models.UserVerification) error {
+	query := `
+		INSERT INTO user_verifications (discord_id, atcoder_username, token)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (discord_id) DO UPDATE
+		SET atcoder_username = EXCLUDED.atcoder_username,
+		    token = EXCLUDED.token,
+		    created_at = CURRENT_TIMESTAMP
+	`
+	_, err := db.Exec(query, verification.DiscordID, verification.AtCoderUsername, verification.Token)
+	return err
+}
+
+// GetUserVerification retrieves a member's pending claim
+func GetUserVerification(db UserDB, discordID string) (*models.UserVerification, error) {
+	var verification models.UserVerification
+	query := `SELECT * FROM user_verifications WHERE discord_id = $1`
+	err := db.Get(&verification, query, discordID)
+	if err != nil {
+		return nil, err
+	}
+	return &verification, nil
+}
+
+// ConfirmUserVerification registers the claimed account as verified for the member and
+// removes the claim. An unverified registration of the same account by another member is
+// deleted, since that member could not prove ownership. Run it in a transaction.
+func ConfirmUserVerification(db UserDB, verification *models.UserVerification) error {
+	deleteQuery := `DELETE FROM users WHERE atcoder_username = $1 AND discord_id <> $2 AND NOT verified`
+	if _, err := db.Exec(deleteQuery, verification.AtCoderUsername, verification.DiscordID); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO users (discord_id, atcoder_username, verified, verified_at)
+		VALUES ($1, $2, true, CURRENT_TIMESTAMP)
+		ON CONFLICT (discord_id) DO UPDATE
+		SET atcoder_username = EXCLUDED.atcoder_username,
+		    verified = true,
+		    verified_at = CURRENT_TIMESTAMP,
+		    updated_at = CURRENT_TIMESTAMP
+	`
+	if _, err := db.Exec(query, verification.DiscordID, verification.AtCoderUsername); err != nil {
+		return err
+	}
+
+	_, err := db.Exec(`DELETE FROM user_verifications WHERE discord_id = $1`, verification.DiscordID)
+	return err
+}
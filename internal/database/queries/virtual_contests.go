@@ -11,16 +11,103 @@ import (
 // CreateVirtualContest creates a new virtual contest
 func CreateVirtualContest(db UserDB, contest *models.VirtualContest) (int, error) {
 	query := `
-		INSERT INTO virtual_contests (server_id, channel_id, created_by, title, start_time, duration_minutes, problem_ids)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		INSERT INTO virtual_contests (server_id, channel_id, created_by, title, start_time, duration_minutes, problem_ids, status, penalty_rule, problem_points, replay_contest_id, presolved_policy)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, COALESCE(NULLIF($12, ''), 'warn'))
 		RETURNING id
 	`
 	var id int
 	err := db.Get(&id, query, contest.ServerID, contest.ChannelID, contest.CreatedBy,
-		contest.Title, contest.StartTime, contest.DurationMinutes, pq.Array(contest.ProblemIDs))
+		contest.Title, contest.StartTime, contest.DurationMinutes, pq.Array(contest.ProblemIDs),
+		contest.Status, contest.PenaltyRule, contest.ProblemPoints, contest.ReplayContestID,
+		contest.PresolvedPolicy)
 	return id, err
 }
 
+// UpdateVirtualContest updates a virtual contest that has not started yet.
+// It returns false if the contest was started or cancelled in the meantime.
+func UpdateVirtualContest(db UserDB, contest *models.VirtualContest) (bool, error) {
+	query := `
+		UPDATE virtual_contests
+		SET title = $2,
+		    start_time = $3,
+		    duration_minutes = $4,
+		    problem_ids = $5,
+		    status = $6,
+		    penalty_rule = $7,
+		    problem_points = $8,
+		    presolved_policy = COALESCE(NULLIF($9, ''), 'warn')
+		WHERE id = $1 AND status IN ('draft', 'scheduled')
+	`
+	result, err := db.Exec(query, contest.ID, contest.Title, contest.StartTime,
+		contest.DurationMinutes, pq.Array(contest.ProblemIDs), contest.Status, contest.PenaltyRule,
+		contest.ProblemPoints, contest.PresolvedPolicy)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// StartVirtualContest starts a draft or scheduled virtual contest now.
+// It returns false if the contest was no longer waiting to start.
+func StartVirtualContest(db UserDB, contestID int, startTime time.Time) (bool, error) {
+	query := `
+		UPDATE virtual_contests
+		SET status = 'running', start_time = $2
+		WHERE id = $1 AND status IN ('draft', 'scheduled')
+	`
+	result, err := db.Exec(query, contestID, startTime)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// UpdateVirtualContestStatus moves a virtual contest from one state to another.
+// It returns false if the contest was no longer in the expected state.
+func UpdateVirtualContestStatus(db UserDB, contestID int, from, to string) (bool, error) {
+	query := `UPDATE virtual_contests SET status = $3 WHERE id = $1 AND status = $2`
+	result, err := db.Exec(query, contestID, from, to)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// GetVirtualContestsToStart retrieves scheduled virtual contests whose start time has come
+func GetVirtualContestsToStart(db UserDB, now time.Time) ([]*models.VirtualContest, error) {
+	var contests []*models.VirtualContest
+	query := `
+		SELECT * FROM virtual_contests
+		WHERE status = 'scheduled' AND start_time <= $1
+	`
+	err := db.Select(&contests, query, now)
+	return contests, err
+}
+
+// GetVirtualContestsToFinish retrieves running virtual contests whose end time has passed
+func GetVirtualContestsToFinish(db UserDB, now time.Time) ([]*models.VirtualContest, error) {
+	var contests []*models.VirtualContest
+	query := `
+		SELECT * FROM virtual_contests
+		WHERE status = 'running'
+		AND start_time + (duration_minutes || ' minutes')::INTERVAL <= $1
+	`
+	err := db.Select(&contests, query, now)
+	return contests, err
+}
+
 // GetVirtualContest retrieves a virtual contest by ID
 func GetVirtualContest(db UserDB, contestID int) (*models.VirtualContest, error) {
 	var contest models.VirtualContest
@@ -32,16 +119,16 @@ func GetVirtualContest(db UserDB, contestID int) (*models.VirtualContest, error)
 	return &contest, nil
 }
 
-// GetServerVirtualContests retrieves all virtual contests for a server
-func GetServerVirtualContests(db UserDB, serverID string) ([]*models.VirtualContest, error) {
+// GetServerVirtualContests retrieves the virtual contests of a server in the given states,
+// newest first with unscheduled drafts at the top
+func GetServerVirtualContests(db UserDB, serverID string, statuses []string) ([]*models.VirtualContest, error) {
 	var contests []*models.VirtualContest
 	query := `
 		SELECT * FROM virtual_contests
-		WHERE server_id = $1
-		ORDER BY start_time DESC
-		LIMIT 50
+		WHERE server_id = $1 AND status = ANY($2)
+		ORDER BY start_time DESC NULLS FIRST, id DESC
 	`
-	err := db.Select(&contests, query, serverID)
+	err := db.Select(&contests, query, serverID, pq.Array(statuses))
 	return contests, err
 }
 
@@ -51,7 +138,7 @@ func GetActiveVirtualContests(db UserDB) ([]*models.VirtualContest, error) {
 	now := time.Now()
 	query := `
 		SELECT * FROM virtual_contests
-		WHERE start_time <= $1
+		WHERE status = 'running'
 		AND start_time + (duration_minutes || ' minutes')::INTERVAL > $1
 	`
 	err := db.Select(&contests, query, now)
@@ -61,58 +148,232 @@ func GetActiveVirtualContests(db UserDB) ([]*models.VirtualContest, error) {
 // CreateVirtualContestSubmission records a submission for a virtual contest
 func CreateVirtualContestSubmission(db UserDB, sub *models.VirtualContestSubmission) error {
 	query := `
-		INSERT INTO virtual_contest_submissions (contest_id, user_id, problem_id, submitted_at, result, point)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO virtual_contest_submissions (contest_id, user_id, problem_id, submitted_at, result, point, wrong_attempts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
 		ON CONFLICT (contest_id, user_id, problem_id) DO UPDATE
 		SET submitted_at = EXCLUDED.submitted_at,
 		    result = EXCLUDED.result,
-		    point = EXCLUDED.point
+		    point = EXCLUDED.point,
+		    wrong_attempts = EXCLUDED.wrong_attempts
 	`
 	_, err := db.Exec(query, sub.ContestID, sub.UserID, sub.ProblemID,
-		sub.SubmittedAt, sub.Result, sub.Point)
+		sub.SubmittedAt, sub.Result, sub.Point, sub.WrongAttempts)
 	return err
 }
 
-// GetVirtualContestStandings retrieves standings for a virtual contest
-func GetVirtualContestStandings(db UserDB, contestID int) ([]models.VirtualContestStanding, error) {
+// GetVirtualContestsToSync retrieves running virtual contests and finished ones
+// whose final results have not been posted yet
+func GetVirtualContestsToSync(db UserDB) ([]*models.VirtualContest, error) {
+	var contests []*models.VirtualContest
 	query := `
-		SELECT
-			vcs.user_id,
-			u.atcoder_username,
-			COUNT(DISTINCT vcs.problem_id) FILTER (WHERE vcs.result = 'AC') as solved_count,
-			COALESCE(SUM(vcs.point) FILTER (WHERE vcs.result = 'AC'), 0) as total_points
+		SELECT * FROM virtual_contests
+		WHERE status = 'running'
+		OR (status = 'finished' AND results_posted_at IS NULL)
+	`
+	err := db.Select(&contests, query)
+	return contests, err
+}
+
+// SetVirtualContestLiveMessage saves the live standings message and feed thread of a contest
+func SetVirtualContestLiveMessage(db UserDB, contestID int, messageID, threadID string) error {
+	query := `
+		UPDATE virtual_contests
+		SET standings_message_id = $2,
+		    thread_id = NULLIF($3, '')
+		WHERE id = $1
+	`
+	_, err := db.Exec(query, contestID, messageID, threadID)
+	return err
+}
+
+// MarkVirtualContestResultsPosted records that the final results of a contest were posted
+func MarkVirtualContestResultsPosted(db UserDB, contestID int, postedAt time.Time) error {
+	query := `UPDATE virtual_contests SET results_posted_at = $2 WHERE id = $1`
+	_, err := db.Exec(query, contestID, postedAt)
+	return err
+}
+
+// GetVirtualContestResults retrieves every per-problem result of a virtual contest
+func GetVirtualContestResults(db UserDB, contestID int) ([]*models.VirtualContestResultRow, error) {
+	var rows []*models.VirtualContestResultRow
+	query := `
+		SELECT vcs.*, u.atcoder_username
 		FROM virtual_contest_submissions vcs
 		JOIN users u ON vcs.user_id = u.discord_id
 		WHERE vcs.contest_id = $1
-		GROUP BY vcs.user_id, u.atcoder_username
-		ORDER BY solved_count DESC, total_points DESC
 	`
+	err := db.Select(&rows, query, contestID)
+	return rows, err
+}
 
-	type Result struct {
-		UserID          string  `db:"user_id"`
-		AtCoderUsername string  `db:"atcoder_username"`
-		SolvedCount     int     `db:"solved_count"`
-		TotalPoints     float64 `db:"total_points"`
+// JoinVirtualContest adds a participant to a virtual contest.
+// It returns false if the user had already joined.
+func JoinVirtualContest(db UserDB, contestID int, userID string, startOffsetSeconds int) (bool, error) {
+	query := `
+		INSERT INTO virtual_contest_participants (contest_id, user_id, start_offset_seconds)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (contest_id, user_id) DO NOTHING
+	`
+	result, err := db.Exec(query, contestID, userID, startOffsetSeconds)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
 	}
+	return rows > 0, nil
+}
 
-	var results []Result
-	err := db.Select(&results, query, contestID)
+// LeaveVirtualContest removes a participant and their results from a virtual contest that
+// has not started. It returns false if the user had not joined or the contest has started.
+func LeaveVirtualContest(db UserDB, contestID int, userID string) (bool, error) {
+	query := `
+		DELETE FROM virtual_contest_participants p
+		USING virtual_contests c
+		WHERE p.contest_id = $1 AND p.user_id = $2
+			AND c.id = p.contest_id AND c.status IN ('draft', 'scheduled')
+	`
+	result, err := db.Exec(query, contestID, userID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	query = `DELETE FROM virtual_contest_submissions WHERE contest_id = $1 AND user_id = $2`
+	if _, err := db.Exec(query, contestID, userID); err != nil {
+		return true, err
+	}
+
+	_, err = RemoveVirtualContestTeamMember(db, contestID, userID)
+	return true, err
+}
+
+// GetVirtualContestParticipants retrieves the participants of a virtual contest in join order
+func GetVirtualContestParticipants(db UserDB, contestID int) ([]*models.VirtualContestParticipantRow, error) {
+	var participants []*models.VirtualContestParticipantRow
+	query := `
+		SELECT p.*, u.atcoder_username
+		FROM virtual_contest_participants p
+		JOIN users u ON p.user_id = u.discord_id
+		WHERE p.contest_id = $1
+		ORDER BY p.joined_at, p.user_id
+	`
+	err := db.Select(&participants, query, contestID)
+	return participants, err
+}
+
+// GetPresolvedProblems retrieves the contest problems each participant had solved
+// before their start time. It returns nothing for contests that have not started.
+func GetPresolvedProblems(db UserDB, contestID int) ([]*models.PresolvedProblem, error) {
+	var presolved []*models.PresolvedProblem
+	query := `
+		SELECT DISTINCT p.user_id, s.problem_id
+		FROM virtual_contest_participants p
+		JOIN virtual_contests c ON c.id = p.contest_id
+		JOIN submissions s ON s.user_id = p.user_id
+			AND s.problem_id = ANY(c.problem_ids)
+			AND s.result = 'AC'
+			AND s.submitted_at < c.start_time + p.start_offset_seconds * INTERVAL '1 second'
+		WHERE p.contest_id = $1
+	`
+	err := db.Select(&presolved, query, contestID)
+	return presolved, err
+}
+
+// GetSolvedProblemIDsBefore retrieves which of the given problems a user solved before a time
+func GetSolvedProblemIDsBefore(db UserDB, userID string, problemIDs []string, before time.Time) ([]string, error) {
+	var ids []string
+	query := `
+		SELECT DISTINCT problem_id FROM submissions
+		WHERE user_id = $1
+			AND problem_id = ANY($2)
+			AND result = 'AC'
+			AND submitted_at < $3
+	`
+	err := db.Select(&ids, query, userID, pq.Array(problemIDs), before)
+	return ids, err
+}
+
+// CreateVirtualContestTeam creates a team in a virtual contest.
+// It returns sql.ErrNoRows if a team with the same name already exists.
+func CreateVirtualContestTeam(db UserDB, contestID int, name string) (int, error) {
+	query := `
+		INSERT INTO virtual_contest_teams (contest_id, name)
+		VALUES ($1, $2)
+		ON CONFLICT (contest_id, name) DO NOTHING
+		RETURNING id
+	`
+	var id int
+	err := db.Get(&id, query, contestID, name)
+	return id, err
+}
+
+// GetVirtualContestTeamByName retrieves a team of a virtual contest by name
+func GetVirtualContestTeamByName(db UserDB, contestID int, name string) (*models.VirtualContestTeam, error) {
+	var team models.VirtualContestTeam
+	query := `SELECT * FROM virtual_contest_teams WHERE contest_id = $1 AND name = $2`
+	err := db.Get(&team, query, contestID, name)
 	if err != nil {
 		return nil, err
 	}
+	return &team, nil
+}
+
+// GetVirtualContestTeams retrieves the teams of a virtual contest
+func GetVirtualContestTeams(db UserDB, contestID int) ([]*models.VirtualContestTeam, error) {
+	var teams []*models.VirtualContestTeam
+	query := `SELECT * FROM virtual_contest_teams WHERE contest_id = $1 ORDER BY name`
+	err := db.Select(&teams, query, contestID)
+	return teams, err
+}
+
+// SetVirtualContestTeamMember puts a user in a team, moving them out of any other team of the contest
+func SetVirtualContestTeamMember(db UserDB, contestID, teamID int, userID string) error {
+	query := `
+		INSERT INTO virtual_contest_team_members (contest_id, team_id, user_id)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (contest_id, user_id) DO UPDATE
+		SET team_id = EXCLUDED.team_id
+	`
+	_, err := db.Exec(query, contestID, teamID, userID)
+	return err
+}
 
-	standings := make([]models.VirtualContestStanding, len(results))
-	for i, r := range results {
-		standings[i] = models.VirtualContestStanding{
-			UserID:          r.UserID,
-			AtCoderUsername: r.AtCoderUsername,
-			Rank:            i + 1,
-			SolvedCount:     r.SolvedCount,
-			TotalPoints:     r.TotalPoints,
-		}
+// RemoveVirtualContestTeamMember removes a user from their team in a virtual contest.
+// It returns false if the user was not in a team.
+func RemoveVirtualContestTeamMember(db UserDB, contestID int, userID string) (bool, error) {
+	query := `DELETE FROM virtual_contest_team_members WHERE contest_id = $1 AND user_id = $2`
+	result, err := db.Exec(query, contestID, userID)
+	if err != nil {
+		return false, err
 	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
 
-	return standings, nil
+// GetVirtualContestTeamMembers retrieves the team members of a virtual contest ordered by team
+func GetVirtualContestTeamMembers(db UserDB, contestID int) ([]*models.VirtualContestTeamMemberRow, error) {
+	var members []*models.VirtualContestTeamMemberRow
+	query := `
+		SELECT m.*, t.name as team_name, u.atcoder_username
+		FROM virtual_contest_team_members m
+		JOIN virtual_contest_teams t ON m.team_id = t.id
+		JOIN users u ON m.user_id = u.discord_id
+		WHERE m.contest_id = $1
+		ORDER BY t.name, u.atcoder_username
+	`
+	err := db.Select(&members, query, contestID)
+	return members, err
 }
 
 // SaveContestNotification saves contest notification configuration
@@ -173,3 +434,21 @@ func GetAllEnabledWeeklyReportConfigs(db UserDB) ([]*models.WeeklyReportConfig,
 	err := db.Select(&configs, query)
 	return configs, err
 }
+
+// GetPostedVirtualContestsForSubmission retrieves finished virtual contests whose results were
+// already posted and that counted the given submission: the user took part, the problem is in
+// the contest and the submission was made during it
+func GetPostedVirtualContestsForSubmission(db UserDB, sub *models.Submission) ([]*models.VirtualContest, error) {
+	var contests []*models.VirtualContest
+	query := `
+		SELECT vc.* FROM virtual_contests vc
+		JOIN virtual_contest_participants p ON p.contest_id = vc.id AND p.user_id = $1
+		WHERE vc.status = 'finished'
+			AND vc.results_posted_at IS NOT NULL
+			AND $2 = ANY(vc.problem_ids)
+			AND $3 >= vc.start_time
+			AND $3 < vc.start_time + (vc.duration_minutes || ' minutes')::INTERVAL
+	`
+	err := db.Select(&contests, query, sub.UserID, sub.ProblemID, sub.SubmittedAt)
+	return contests, err
+}
@@ -0,0 +1,111 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"coding-winner/internal/models"
+)
+
+// CreateVirtualPoll creates a virtual poll with its options
+func CreateVirtualPoll(db UserDB, poll *models.VirtualPoll, options []*models.VirtualPollOption) (int, error) {
+	query := `
+		INSERT INTO virtual_polls (server_id, channel_id, created_by, mode, duration_minutes, penalty_rule, start_time, deadline)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+	var id int
+	err := db.Get(&id, query, poll.ServerID, poll.ChannelID, poll.CreatedBy, poll.Mode,
+		poll.DurationMinutes, poll.PenaltyRule, poll.StartTime, poll.Deadline)
+	if err != nil {
+		return 0, err
+	}
+
+	optionQuery := `
+		INSERT INTO virtual_poll_options (poll_id, option_index, label, problem_ids, problem_points, replay_contest_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+	`
+	for _, opt := range options {
+		_, err := db.Exec(optionQuery, id, opt.OptionIndex, opt.Label, pq.Array(opt.ProblemIDs),
+			opt.ProblemPoints, opt.ReplayContestID)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return id, nil
+}
+
+// SetVirtualPollMessage saves the message that carries the poll's vote buttons
+func SetVirtualPollMessage(db UserDB, pollID int, messageID string) error {
+	query := `UPDATE virtual_polls SET message_id = $2 WHERE id = $1`
+	_, err := db.Exec(query, pollID, messageID)
+	return err
+}
+
+// GetVirtualPoll retrieves a virtual poll by ID
+func GetVirtualPoll(db UserDB, pollID int) (*models.VirtualPoll, error) {
+	var poll models.VirtualPoll
+	query := `SELECT * FROM virtual_polls WHERE id = $1`
+	err := db.Get(&poll, query, pollID)
+	if err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// GetVirtualPollOptions retrieves the options of a poll with their vote counts
+func GetVirtualPollOptions(db UserDB, pollID int) ([]*models.VirtualPollOption, error) {
+	var options []*models.VirtualPollOption
+	query := `
+		SELECT o.*, COUNT(v.user_id) as votes
+		FROM virtual_poll_options o
+		LEFT JOIN virtual_poll_votes v ON v.poll_id = o.poll_id AND v.option_index = o.option_index
+		WHERE o.poll_id = $1
+		GROUP BY o.poll_id, o.option_index
+		ORDER BY o.option_index
+	`
+	err := db.Select(&options, query, pollID)
+	return options, err
+}
+
+// CastVirtualPollVote records a user's vote, replacing any earlier vote on the same poll
+func CastVirtualPollVote(db UserDB, pollID int, userID string, optionIndex int) error {
+	query := `
+		INSERT INTO virtual_poll_votes (poll_id, user_id, option_index)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (poll_id, user_id) DO UPDATE
+		SET option_index = EXCLUDED.option_index,
+		    voted_at = CURRENT_TIMESTAMP
+	`
+	_, err := db.Exec(query, pollID, userID, optionIndex)
+	return err
+}
+
+// GetExpiredVirtualPolls retrieves open polls whose deadline has passed
+func GetExpiredVirtualPolls(db UserDB, now time.Time) ([]*models.VirtualPoll, error) {
+	var polls []*models.VirtualPoll
+	query := `SELECT * FROM virtual_polls WHERE closed = false AND deadline <= $1`
+	err := db.Select(&polls, query, now)
+	return polls, err
+}
+
+// CloseVirtualPoll marks a poll as closed. It returns false if it was already closed.
+func CloseVirtualPoll(db UserDB, pollID int) (bool, error) {
+	query := `UPDATE virtual_polls SET closed = true WHERE id = $1 AND closed = false`
+	result, err := db.Exec(query, pollID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// SetVirtualPollContest records the virtual contest created from a poll
+func SetVirtualPollContest(db UserDB, pollID, contestID int) error {
+	query := `UPDATE virtual_polls SET created_contest_id = $2 WHERE id = $1`
+	_, err := db.Exec(query, pollID, contestID)
+	return err
+}
@@ -0,0 +1,97 @@
+package queries
+
+import (
+	"github.com/lib/pq"
+	"coding-winner/internal/models"
+)
+
+// GetServerVirtualRatings retrieves the ratings of a server's verified members, highest first
+func GetServerVirtualRatings(db UserDB, serverID string) ([]*models.VirtualRating, error) {
+	var ratings []*models.VirtualRating
+	query := `
+		SELECT r.* FROM virtual_ratings r
+		JOIN users u ON u.discord_id = r.user_id
+		WHERE r.server_id = $1 AND u.verified
+		ORDER BY r.rating DESC, r.contests DESC
+	`
+	err := db.Select(&ratings, query, serverID)
+	return ratings, err
+}
+
+// SaveVirtualRatingChange records a rating change and updates the member's rating.
+// It returns false without changing anything if the contest was already rated for the member.
+func SaveVirtualRatingChange(db UserDB, change *models.VirtualRatingChange) (bool, error) {
+	query := `
+		INSERT INTO virtual_rating_history (contest_id, user_id, server_id, contest_rank, old_rating, new_rating)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (contest_id, user_id) DO NOTHING
+	`
+	result, err := db.Exec(query, change.ContestID, change.UserID, change.ServerID,
+		change.ContestRank, change.OldRating, change.NewRating)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rows == 0 {
+		return false, nil
+	}
+
+	ratingQuery := `
+		INSERT INTO virtual_ratings (server_id, user_id, rating, contests, updated_at)
+		VALUES ($1, $2, $3, 1, CURRENT_TIMESTAMP)
+		ON CONFLICT (server_id, user_id) DO UPDATE
+		SET rating = EXCLUDED.rating,
+		    contests = virtual_ratings.contests + 1,
+		    updated_at = CURRENT_TIMESTAMP
+	`
+	_, err = db.Exec(ratingQuery, change.ServerID, change.UserID, change.NewRating)
+	return err == nil, err
+}
+
+// GetVirtualContestRatingChanges retrieves the rating changes of a contest in rank order
+func GetVirtualContestRatingChanges(db UserDB, contestID int) ([]*models.VirtualRatingChangeRow, error) {
+	var changes []*models.VirtualRatingChangeRow
+	query := `
+		SELECT h.*, vc.title, u.atcoder_username
+		FROM virtual_rating_history h
+		JOIN virtual_contests vc ON vc.id = h.contest_id
+		JOIN users u ON u.discord_id = h.user_id
+		WHERE h.contest_id = $1
+		ORDER BY h.contest_rank, u.atcoder_username
+	`
+	err := db.Select(&changes, query, contestID)
+	return changes, err
+}
+
+// GetUserVirtualRatingHistory retrieves a member's most recent rating changes in a server, newest first
+func GetUserVirtualRatingHistory(db UserDB, serverID, userID string, limit int) ([]*models.VirtualRatingChangeRow, error) {
+	var changes []*models.VirtualRatingChangeRow
+	query := `
+		SELECT h.*, vc.title, u.atcoder_username
+		FROM virtual_rating_history h
+		JOIN virtual_contests vc ON vc.id = h.contest_id
+		JOIN users u ON u.discord_id = h.user_id
+		WHERE h.server_id = $1 AND h.user_id = $2
+		ORDER BY h.created_at DESC
+		LIMIT $3
+	`
+	err := db.Select(&changes, query, serverID, userID, limit)
+	return changes, err
+}
+
+// GetVerifiedUserIDs returns which of the given users have verified their AtCoder account
+func GetVerifiedUserIDs(db UserDB, userIDs []string) (map[string]bool, error) {
+	var ids []string
+	query := `SELECT discord_id FROM users WHERE discord_id = ANY($1) AND verified`
+	if err := db.Select(&ids, query, pq.Array(userIDs)); err != nil {
+		return nil, err
+	}
+	verified := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		verified[id] = true
+	}
+	return verified, nil
+}
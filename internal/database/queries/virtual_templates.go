@@ -0,0 +1,72 @@
+package queries
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+	"coding-winner/internal/models"
+)
+
+// CreateVirtualContestTemplate creates a recurring virtual contest template
+func CreateVirtualContestTemplate(db UserDB, t *models.VirtualContestTemplate) (int, error) {
+	query := `
+		INSERT INTO virtual_contest_templates (server_id, channel_id, created_by, title, schedule, duration_minutes,
+			problem_ids, problem_count, difficulty_min, difficulty_max, penalty_rule, announce_minutes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		RETURNING id
+	`
+	var id int
+	err := db.Get(&id, query, t.ServerID, t.ChannelID, t.CreatedBy, t.Title, t.Schedule, t.DurationMinutes,
+		pq.Array(t.ProblemIDs), t.ProblemCount, t.DifficultyMin, t.DifficultyMax, t.PenaltyRule, t.AnnounceMinutes)
+	return id, err
+}
+
+// GetServerVirtualContestTemplates retrieves the enabled templates of a server
+func GetServerVirtualContestTemplates(db UserDB, serverID string) ([]*models.VirtualContestTemplate, error) {
+	var templates []*models.VirtualContestTemplate
+	query := `SELECT * FROM virtual_contest_templates WHERE server_id = $1 AND enabled = true ORDER BY id`
+	err := db.Select(&templates, query, serverID)
+	return templates, err
+}
+
+// GetAllEnabledVirtualContestTemplates retrieves all enabled templates
+func GetAllEnabledVirtualContestTemplates(db UserDB) ([]*models.VirtualContestTemplate, error) {
+	var templates []*models.VirtualContestTemplate
+	query := `SELECT * FROM virtual_contest_templates WHERE enabled = true`
+	err := db.Select(&templates, query)
+	return templates, err
+}
+
+// DisableVirtualContestTemplate stops a template from creating further occurrences.
+// It returns false if no enabled template with the ID exists on the server.
+func DisableVirtualContestTemplate(db UserDB, templateID int, serverID string) (bool, error) {
+	query := `UPDATE virtual_contest_templates SET enabled = false WHERE id = $1 AND server_id = $2 AND enabled = true`
+	result, err := db.Exec(query, templateID, serverID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// SetVirtualContestTemplateOccurrence records the latest instantiated occurrence of a template.
+// It returns false if the occurrence was already recorded, so each occurrence is created once.
+func SetVirtualContestTemplateOccurrence(db UserDB, templateID int, occurrence time.Time) (bool, error) {
+	query := `
+		UPDATE virtual_contest_templates
+		SET last_occurrence = $2
+		WHERE id = $1 AND (last_occurrence IS NULL OR last_occurrence < $2)
+	`
+	result, err := db.Exec(query, templateID, occurrence)
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
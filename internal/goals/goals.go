@@ -0,0 +1,88 @@
+package goals
+
+import (
+	"fmt"
+
+	"coding-winner/internal/atcoder"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// Goal kinds
+const (
+	KindNewAC   = "new-ac"
+	KindColorAC = "color-ac"
+	KindRating  = "rating"
+)
+
+// Color is a difficulty/rating color band
+type Color struct {
+	Key  string
+	Name string
+	Min  int
+	Max  int
+}
+
+// Colors lists the AtCoder color bands from lowest to highest
+var Colors = []Color{
+	{Key: "gray", Name: "灰色", Min: 0, Max: 400},
+	{Key: "brown", Name: "茶色", Min: 400, Max: 800},
+	{Key: "green", Name: "緑色", Min: 800, Max: 1200},
+	{Key: "cyan", Name: "水色", Min: 1200, Max: 1600},
+	{Key: "blue", Name: "青色", Min: 1600, Max: 2000},
+	{Key: "yellow", Name: "黄色", Min: 2000, Max: 2400},
+	{Key: "orange", Name: "橙色", Min: 2400, Max: 2800},
+	{Key: "red", Name: "赤色", Min: 2800, Max: 1 << 30},
+}
+
+// FindColor looks up a color band by key
+func FindColor(key string) (Color, bool) {
+	for _, c := range Colors {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Color{}, false
+}
+
+// Progress computes the current progress value of a goal.
+// For rating goals this is the user's current rating; otherwise it is a number of new ACs.
+func Progress(db queries.UserDB, client *atcoder.Client, user *models.User, goal *models.Goal) (int, error) {
+	switch goal.Kind {
+	case KindNewAC:
+		return queries.GetNewACCount(db, user.DiscordID, goal.PeriodStart.Time, goal.PeriodEnd.Time)
+	case KindColorAC:
+		color, ok := FindColor(goal.Color.String)
+		if !ok {
+			return 0, fmt.Errorf("unknown color: %s", goal.Color.String)
+		}
+		return queries.GetNewACCountInDifficultyRange(db, user.DiscordID,
+			goal.PeriodStart.Time, goal.PeriodEnd.Time, color.Min, color.Max)
+	case KindRating:
+		return client.GetUserRating(user.AtCoderUsername)
+	default:
+		return 0, fmt.Errorf("unknown goal kind: %s", goal.Kind)
+	}
+}
+
+// Describe returns a human-readable description of a goal
+func Describe(goal *models.Goal) string {
+	period := ""
+	if goal.PeriodStart.Valid && goal.PeriodEnd.Valid {
+		period = fmt.Sprintf("%s〜%s ", goal.PeriodStart.Time.Format("01/02"),
+			goal.PeriodEnd.Time.AddDate(0, 0, -1).Format("01/02"))
+	}
+
+	switch goal.Kind {
+	case KindNewAC:
+		return fmt.Sprintf("%s新規AC %d問", period, goal.Target)
+	case KindColorAC:
+		color, _ := FindColor(goal.Color.String)
+		return fmt.Sprintf("%s%sの問題を %d問", period, color.Name, goal.Target)
+	case KindRating:
+		color, _ := FindColor(goal.Color.String)
+		return fmt.Sprintf("%sに到達（レート%d）", color.Name, goal.Target)
+	default:
+		return goal.Kind
+	}
+}
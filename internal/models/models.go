@@ -3,14 +3,53 @@ package models
 import (
 	"database/sql"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // User represents a Discord user registered with their AtCoder username
 type User struct {
+	DiscordID       string       `db:"discord_id"`
+	AtCoderUsername string       `db:"atcoder_username"`
+	CreatedAt       time.Time    `db:"created_at"`
+	UpdatedAt       time.Time    `db:"updated_at"`
+	Verified        bool         `db:"verified"` // ownership of the AtCoder account was confirmed
+	VerifiedAt      sql.NullTime `db:"verified_at"`
+}
+
+// SubmissionBackfill represents the progress of a user's full submission history backfill
+type SubmissionBackfill struct {
+	UserID          string       `db:"user_id"`
+	AtCoderUsername string       `db:"atcoder_username"`
+	FromSecond      int64        `db:"from_second"` // next page starts here
+	Fetched         int          `db:"fetched"`
+	CompletedAt     sql.NullTime `db:"completed_at"`
+	UpdatedAt       time.Time    `db:"updated_at"`
+}
+
+// UserSyncState represents the submission sync cursor and health of a user
+type UserSyncState struct {
+	UserID              string         `db:"user_id"`
+	LastSyncedEpoch     sql.NullInt64  `db:"last_synced_epoch"` // exclusive: everything before it is synced
+	LastSuccessAt       sql.NullTime   `db:"last_success_at"`
+	LastError           sql.NullString `db:"last_error"`
+	LastErrorAt         sql.NullTime   `db:"last_error_at"`
+	ConsecutiveFailures int            `db:"consecutive_failures"`
+	UpdatedAt           time.Time      `db:"updated_at"`
+}
+
+// UserSyncStateRow is a sync state joined with the AtCoder username
+type UserSyncStateRow struct {
+	UserSyncState
+	AtCoderUsername string `db:"atcoder_username"`
+}
+
+// UserVerification represents a pending claim of an AtCoder account
+type UserVerification struct {
 	DiscordID       string    `db:"discord_id"`
 	AtCoderUsername string    `db:"atcoder_username"`
+	Token           string    `db:"token"`
 	CreatedAt       time.Time `db:"created_at"`
-	UpdatedAt       time.Time `db:"updated_at"`
 }
 
 // ContestNotification represents contest notification settings for a server
@@ -35,13 +74,53 @@ type Submission struct {
 	SyncedAt    time.Time `db:"synced_at"`
 }
 
+// SubmissionResultChange records a synced submission whose result or point changed,
+// after judging finished or a rejudge
+type SubmissionResultChange struct {
+	Submission *Submission
+	OldResult  string
+}
+
+// PendingSubmissionUser is a user with recent submissions that are still being judged
+type PendingSubmissionUser struct {
+	UserID          string    `db:"user_id"`
+	AtCoderUsername string    `db:"atcoder_username"`
+	OldestPending   time.Time `db:"oldest_pending"`
+}
+
 // Problem represents an AtCoder problem
 type Problem struct {
-	ProblemID  string         `db:"problem_id"`
-	ContestID  sql.NullString `db:"contest_id"`
-	Title      string         `db:"title"`
-	Difficulty sql.NullInt64  `db:"difficulty"`
-	CreatedAt  time.Time      `db:"created_at"`
+	ProblemID      string          `db:"problem_id"`
+	ContestID      sql.NullString  `db:"contest_id"`
+	Title          string          `db:"title"`
+	Difficulty     sql.NullInt64   `db:"difficulty"`
+	CreatedAt      time.Time       `db:"created_at"`
+	Slope          sql.NullFloat64 `db:"slope"`
+	Intercept      sql.NullFloat64 `db:"intercept"`
+	Discrimination sql.NullFloat64 `db:"discrimination"`
+}
+
+// Contest represents a past or upcoming AtCoder contest
+type Contest struct {
+	ContestID       string         `db:"contest_id"`
+	Title           string         `db:"title"`
+	StartTime       time.Time      `db:"start_time"`
+	DurationSeconds int            `db:"duration_seconds"`
+	RateChange      sql.NullString `db:"rate_change"`
+}
+
+// ContestProblem represents a problem of an AtCoder contest with its original point value
+type ContestProblem struct {
+	ContestID    string          `db:"contest_id"`
+	ProblemID    string          `db:"problem_id"`
+	ProblemIndex string          `db:"problem_index"`
+	Point        sql.NullFloat64 `db:"point"`
+}
+
+// ProblemAttempt represents a problem a user has submitted to, with whether it was solved
+type ProblemAttempt struct {
+	Problem
+	Solved bool `db:"solved"`
 }
 
 // DailyProblemConfig represents daily problem settings for a server
@@ -54,28 +133,178 @@ type DailyProblemConfig struct {
 	Enabled       bool      `db:"enabled"`
 }
 
+// Virtual contest states
+const (
+	VirtualContestDraft     = "draft"
+	VirtualContestScheduled = "scheduled"
+	VirtualContestRunning   = "running"
+	VirtualContestFinished  = "finished"
+	VirtualContestCancelled = "cancelled"
+)
+
+// Virtual contest penalty rules
+const (
+	PenaltyRuleAtCoder = "atcoder"
+	PenaltyRuleICPC    = "icpc"
+)
+
+// Policies for problems a participant solved before the contest started
+const (
+	PresolvedWarn    = "warn"
+	PresolvedExclude = "exclude"
+)
+
 // VirtualContest represents a virtual contest
 type VirtualContest struct {
-	ID              int       `db:"id"`
-	ServerID        string    `db:"server_id"`
-	ChannelID       string    `db:"channel_id"`
+	ID              int            `db:"id"`
+	ServerID        string         `db:"server_id"`
+	ChannelID       string         `db:"channel_id"`
 	CreatedBy       sql.NullString `db:"created_by"`
-	Title           string    `db:"title"`
-	StartTime       time.Time `db:"start_time"`
-	DurationMinutes int       `db:"duration_minutes"`
-	ProblemIDs      []string  `db:"problem_ids"`
-	CreatedAt       time.Time `db:"created_at"`
+	Title           string         `db:"title"`
+	StartTime       sql.NullTime   `db:"start_time"`
+	DurationMinutes int            `db:"duration_minutes"`
+	ProblemIDs      pq.StringArray `db:"problem_ids"`
+	CreatedAt       time.Time      `db:"created_at"`
+	Status          string         `db:"status"`
+	PenaltyRule        string         `db:"penalty_rule"`
+	StandingsMessageID sql.NullString `db:"standings_message_id"`
+	ThreadID           sql.NullString `db:"thread_id"`
+	ResultsPostedAt    sql.NullTime   `db:"results_posted_at"`
+	ProblemPoints      pq.Float64Array `db:"problem_points"` // parallel to ProblemIDs; empty or negative entries use AtCoder's points
+	ReplayContestID    sql.NullString  `db:"replay_contest_id"`
+	PresolvedPolicy    string          `db:"presolved_policy"`
 }
 
-// VirtualContestSubmission represents a submission in a virtual contest
-type VirtualContestSubmission struct {
-	ID          int       `db:"id"`
+// VirtualContestTemplate represents a recurring virtual contest.
+// Problems come from ProblemIDs if set, otherwise ProblemCount problems are picked from the difficulty range.
+type VirtualContestTemplate struct {
+	ID              int            `db:"id"`
+	ServerID        string         `db:"server_id"`
+	ChannelID       string         `db:"channel_id"`
+	CreatedBy       sql.NullString `db:"created_by"`
+	Title           string         `db:"title"`
+	Schedule        string         `db:"schedule"` // standard 5-field cron expression in JST
+	DurationMinutes int            `db:"duration_minutes"`
+	ProblemIDs      pq.StringArray `db:"problem_ids"`
+	ProblemCount    sql.NullInt64  `db:"problem_count"`
+	DifficultyMin   sql.NullInt64  `db:"difficulty_min"`
+	DifficultyMax   sql.NullInt64  `db:"difficulty_max"`
+	PenaltyRule     string         `db:"penalty_rule"`
+	AnnounceMinutes int            `db:"announce_minutes"`
+	LastOccurrence  sql.NullTime   `db:"last_occurrence"` // start time of the latest instantiated occurrence
+	Enabled         bool           `db:"enabled"`
+	CreatedAt       time.Time      `db:"created_at"`
+}
+
+// VirtualRating represents a member's server-local rating from virtual contests
+type VirtualRating struct {
+	ServerID  string    `db:"server_id"`
+	UserID    string    `db:"user_id"`
+	Rating    int       `db:"rating"`
+	Contests  int       `db:"contests"`
+	UpdatedAt time.Time `db:"updated_at"`
+}
+
+// VirtualRatingChange represents a rating change from one virtual contest
+type VirtualRatingChange struct {
 	ContestID   int       `db:"contest_id"`
 	UserID      string    `db:"user_id"`
-	ProblemID   string    `db:"problem_id"`
-	SubmittedAt time.Time `db:"submitted_at"`
-	Result      string    `db:"result"`
-	Point       float64   `db:"point"`
+	ServerID    string    `db:"server_id"`
+	ContestRank int       `db:"contest_rank"`
+	OldRating   int       `db:"old_rating"`
+	NewRating   int       `db:"new_rating"`
+	CreatedAt   time.Time `db:"created_at"`
+}
+
+// VirtualRatingChangeRow is a rating change joined with the contest title and username
+type VirtualRatingChangeRow struct {
+	VirtualRatingChange
+	ContestTitle    string `db:"title"`
+	AtCoderUsername string `db:"atcoder_username"`
+}
+
+// Virtual poll modes
+const (
+	VirtualPollAuto   = "auto"   // options are generated problem sets
+	VirtualPollReplay = "replay" // options are past AtCoder contests
+)
+
+// VirtualPoll represents a vote on the problems of the next virtual contest
+type VirtualPoll struct {
+	ID               int            `db:"id"`
+	ServerID         string         `db:"server_id"`
+	ChannelID        string         `db:"channel_id"`
+	MessageID        sql.NullString `db:"message_id"`
+	CreatedBy        sql.NullString `db:"created_by"`
+	Mode             string         `db:"mode"`
+	DurationMinutes  int            `db:"duration_minutes"` // unused for replays, which keep the original duration
+	PenaltyRule      string         `db:"penalty_rule"`
+	StartTime        sql.NullTime   `db:"start_time"`
+	Deadline         time.Time      `db:"deadline"`
+	Closed           bool           `db:"closed"`
+	CreatedContestID sql.NullInt64  `db:"created_contest_id"`
+	CreatedAt        time.Time      `db:"created_at"`
+}
+
+// VirtualPollOption is one candidate of a virtual poll
+type VirtualPollOption struct {
+	PollID          int             `db:"poll_id"`
+	OptionIndex     int             `db:"option_index"`
+	Label           string          `db:"label"`
+	ProblemIDs      pq.StringArray  `db:"problem_ids"`
+	ProblemPoints   pq.Float64Array `db:"problem_points"`
+	ReplayContestID sql.NullString  `db:"replay_contest_id"`
+	Votes           int             `db:"votes"`
+}
+
+// VirtualContestSubmission represents a user's result on one problem of a virtual contest.
+// SubmittedAt is the first AC time if solved, otherwise the latest submission time.
+type VirtualContestSubmission struct {
+	ID            int       `db:"id"`
+	ContestID     int       `db:"contest_id"`
+	UserID        string    `db:"user_id"`
+	ProblemID     string    `db:"problem_id"`
+	SubmittedAt   time.Time `db:"submitted_at"`
+	Result        string    `db:"result"`
+	Point         float64   `db:"point"`
+	WrongAttempts int       `db:"wrong_attempts"`
+}
+
+// VirtualContestParticipant represents a member who joined a virtual contest.
+// StartOffsetSeconds is non-zero for late joiners and shifts their personal start time.
+type VirtualContestParticipant struct {
+	ContestID          int       `db:"contest_id"`
+	UserID             string    `db:"user_id"`
+	JoinedAt           time.Time `db:"joined_at"`
+	StartOffsetSeconds int       `db:"start_offset_seconds"`
+}
+
+// VirtualContestParticipantRow is a participant joined with the user's AtCoder username
+type VirtualContestParticipantRow struct {
+	VirtualContestParticipant
+	AtCoderUsername string `db:"atcoder_username"`
+}
+
+// VirtualContestTeam represents a team in a team virtual contest
+type VirtualContestTeam struct {
+	ID        int       `db:"id"`
+	ContestID int       `db:"contest_id"`
+	Name      string    `db:"name"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+// VirtualContestTeamMember represents a user's team in a virtual contest
+type VirtualContestTeamMember struct {
+	ContestID int    `db:"contest_id"`
+	TeamID    int    `db:"team_id"`
+	UserID    string `db:"user_id"`
+}
+
+// VirtualContestTeamMemberRow is a team member joined with the team name and AtCoder username
+type VirtualContestTeamMemberRow struct {
+	VirtualContestTeamMember
+	TeamName        string `db:"team_name"`
+	AtCoderUsername string `db:"atcoder_username"`
 }
 
 // ContestNotifiedMessage represents a notified contest message for reaction tracking
@@ -123,4 +352,75 @@ type VirtualContestStanding struct {
 	SolvedCount     int
 	TotalPoints     float64
 	PenaltyTime     time.Duration
+	Problems        map[string]VirtualProblemResult // problem ID -> result
+	HasPresolved    bool                            // solved some problem before the contest started
+}
+
+// VirtualProblemResult represents a user's result on one problem of a virtual contest
+type VirtualProblemResult struct {
+	Solved        bool
+	Elapsed       time.Duration // time from contest start to the first AC
+	SubmittedAt   time.Time     // time of the first AC
+	WrongAttempts int
+	Point         float64
+	Presolved     bool // solved before the contest started
+}
+
+// PresolvedProblem is a contest problem a participant had solved before their start time
+type PresolvedProblem struct {
+	UserID    string `db:"user_id"`
+	ProblemID string `db:"problem_id"`
+}
+
+// VirtualContestResultRow is a virtual contest submission joined with the user's AtCoder username
+type VirtualContestResultRow struct {
+	VirtualContestSubmission
+	AtCoderUsername string `db:"atcoder_username"`
+}
+
+// LeaderboardEntry represents a user's row in a server leaderboard
+type LeaderboardEntry struct {
+	UserID          string `db:"user_id"`
+	AtCoderUsername string `db:"atcoder_username"`
+	Rank            int
+	Value           int `db:"value"`
+}
+
+// ContestComparison represents two users' results in a contest both submitted to
+type ContestComparison struct {
+	ContestID string    `db:"contest_id"`
+	ScoreA    float64   `db:"score_a"`
+	ScoreB    float64   `db:"score_b"`
+	SolvedA   int       `db:"solved_a"`
+	SolvedB   int       `db:"solved_b"`
+	StartedAt time.Time `db:"started_at"`
+}
+
+// Goal represents a member's personal goal
+type Goal struct {
+	ID          int            `db:"id"`
+	UserID      string         `db:"user_id"`
+	ServerID    string         `db:"server_id"`
+	ChannelID   string         `db:"channel_id"`
+	Kind        string         `db:"kind"`
+	Target      int            `db:"target"`
+	Color       sql.NullString `db:"color"`
+	PeriodStart sql.NullTime   `db:"period_start"`
+	PeriodEnd   sql.NullTime   `db:"period_end"`
+	CompletedAt sql.NullTime   `db:"completed_at"`
+	CreatedAt   time.Time      `db:"created_at"`
+}
+
+// UserBadge represents a badge unlocked by a user
+type UserBadge struct {
+	UserID     string    `db:"user_id"`
+	BadgeKey   string    `db:"badge_key"`
+	UnlockedAt time.Time `db:"unlocked_at"`
+}
+
+// BadgeConfig represents badge announcement settings for a server
+type BadgeConfig struct {
+	ServerID  string `db:"server_id"`
+	ChannelID string `db:"channel_id"`
+	Enabled   bool   `db:"enabled"`
 }
@@ -0,0 +1,95 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+
+	"coding-winner/internal/achievements"
+	"coding-winner/internal/backfill"
+	"coding-winner/internal/database/queries"
+)
+
+// checkAchievements evaluates badge rules for every user and announces new unlocks
+func (s *Scheduler) checkAchievements() error {
+	users, err := queries.GetAllUsers(s.db)
+	if err != nil {
+		return err
+	}
+
+	configs, err := queries.GetAllEnabledBadgeConfigs(s.db)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		// Badges are evaluated once the user's whole history is in, and only those achieved
+		// after that are announced; older ones are recorded silently
+		pending, err := backfill.IsPending(s.db, user)
+		if err != nil {
+			log.Printf("Error getting backfill state for %s: %v", user.AtCoderUsername, err)
+			continue
+		}
+		if pending {
+			continue
+		}
+		state, err := queries.GetSubmissionBackfill(s.db, user.DiscordID)
+		if err != nil {
+			log.Printf("Error getting backfill state for %s: %v", user.AtCoderUsername, err)
+			continue
+		}
+
+		badges, err := queries.GetUserBadges(s.db, user.DiscordID)
+		if err != nil {
+			log.Printf("Error getting badges for %s: %v", user.AtCoderUsername, err)
+			continue
+		}
+		unlocked := make(map[string]bool, len(badges))
+		for _, b := range badges {
+			unlocked[b.BadgeKey] = true
+		}
+
+		newBadges, err := achievements.Evaluate(s.db, user.DiscordID, unlocked)
+		if err != nil {
+			log.Printf("Error evaluating badges for %s: %v", user.AtCoderUsername, err)
+		}
+
+		for _, unlock := range newBadges {
+			badge := unlock.Badge
+			inserted, err := queries.UnlockBadge(s.db, user.DiscordID, badge.Key, unlock.AchievedAt)
+			if err != nil {
+				log.Printf("Error unlocking badge %s for %s: %v", badge.Key, user.AtCoderUsername, err)
+				continue
+			}
+			if !inserted {
+				continue
+			}
+
+			log.Printf("Badge %s unlocked by %s", badge.Key, user.AtCoderUsername)
+			if unlock.AchievedAt.Before(state.CompletedAt.Time) {
+				continue
+			}
+
+			message := fmt.Sprintf("%s <@%s> さんがバッジ **%s** を獲得しました！（%s）",
+				badge.Emoji, user.DiscordID, badge.Name, badge.Description)
+			for _, config := range configs {
+				if !s.isServerMember(config.ServerID, user.DiscordID) {
+					continue
+				}
+				if _, err := s.discord.ChannelMessageSend(config.ChannelID, message); err != nil {
+					log.Printf("Error sending badge announcement to channel %s: %v", config.ChannelID, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// isServerMember checks whether a Discord user belongs to the server
+func (s *Scheduler) isServerMember(serverID, userID string) bool {
+	if _, err := s.discord.State.Member(serverID, userID); err == nil {
+		return true
+	}
+	_, err := s.discord.GuildMember(serverID, userID)
+	return err == nil
+}
@@ -0,0 +1,55 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/goals"
+)
+
+// checkGoals marks goals whose targets have been reached and celebrates them
+func (s *Scheduler) checkGoals() error {
+	now := time.Now()
+	activeGoals, err := queries.GetActiveGoals(s.db, now)
+	if err != nil {
+		return err
+	}
+
+	for _, goal := range activeGoals {
+		user, err := queries.GetUser(s.db, goal.UserID)
+		if err != nil {
+			log.Printf("Error getting user for goal %d: %v", goal.ID, err)
+			continue
+		}
+
+		progress, err := goals.Progress(s.db, s.atcoderClient, user, goal)
+		if err != nil {
+			log.Printf("Error computing progress of goal %d: %v", goal.ID, err)
+			continue
+		}
+		if goal.Kind == goals.KindRating {
+			s.atcoderClient.RateLimitDelay()
+		}
+
+		if progress < goal.Target {
+			continue
+		}
+
+		if err := queries.CompleteGoal(s.db, goal.ID, now); err != nil {
+			log.Printf("Error completing goal %d: %v", goal.ID, err)
+			continue
+		}
+
+		message := fmt.Sprintf("🎉 <@%s> さんが目標「%s」を達成しました！おめでとうございます！",
+			goal.UserID, goals.Describe(goal))
+		if _, err := s.discord.ChannelMessageSend(goal.ChannelID, message); err != nil {
+			log.Printf("Error sending goal celebration to channel %s: %v", goal.ChannelID, err)
+		}
+
+		log.Printf("Goal %d completed by %s", goal.ID, user.AtCoderUsername)
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package scheduler
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// instantiateRecurringVirtualContests creates the next occurrence of each recurring template
+// once it is within the template's announcement window, and announces it. The occurrence is
+// created as a scheduled contest, so updateVirtualContestStates starts it on time.
+func (s *Scheduler) instantiateRecurringVirtualContests() error {
+	templates, err := queries.GetAllEnabledVirtualContestTemplates(s.db)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, template := range templates {
+		next, err := virtual.NextOccurrence(template, now)
+		if err != nil {
+			log.Printf("Error parsing schedule of virtual contest template %d: %v", template.ID, err)
+			continue
+		}
+		if next.Sub(now) > time.Duration(template.AnnounceMinutes)*time.Minute {
+			continue
+		}
+
+		// Claim the occurrence first so it is created only once
+		claimed, err := queries.SetVirtualContestTemplateOccurrence(s.db, template.ID, next)
+		if err != nil {
+			log.Printf("Error claiming occurrence of virtual contest template %d: %v", template.ID, err)
+			continue
+		}
+		if !claimed {
+			continue
+		}
+
+		if err := s.createRecurringOccurrence(template, next); err != nil {
+			log.Printf("Error creating occurrence of virtual contest template %d: %v", template.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// createRecurringOccurrence creates and announces one occurrence of a template
+func (s *Scheduler) createRecurringOccurrence(template *models.VirtualContestTemplate, startTime time.Time) error {
+	contest := &models.VirtualContest{
+		ServerID:        template.ServerID,
+		ChannelID:       template.ChannelID,
+		CreatedBy:       template.CreatedBy,
+		Title:           fmt.Sprintf("%s（%s）", template.Title, startTime.In(virtual.JST).Format("01/02")),
+		StartTime:       sql.NullTime{Time: startTime, Valid: true},
+		DurationMinutes: template.DurationMinutes,
+		Status:          models.VirtualContestScheduled,
+		PenaltyRule:     template.PenaltyRule,
+	}
+
+	if len(template.ProblemIDs) > 0 {
+		contest.ProblemIDs = template.ProblemIDs
+	} else {
+		problems, err := s.pickRecurringProblems(template)
+		if err != nil {
+			return err
+		}
+		if problems == nil {
+			message := fmt.Sprintf("⚠️ 定期バーチャル「%s」: 条件に合う未解決の問題が足りないため、今回の開催を見送りました。", template.Title)
+			_, err := s.discord.ChannelMessageSend(template.ChannelID, message)
+			return err
+		}
+		contest.ProblemIDs, contest.ProblemPoints = virtual.AutoProblemSet(problems)
+	}
+
+	contestID, err := queries.CreateVirtualContest(s.db, contest)
+	if err != nil {
+		return err
+	}
+
+	contest.ID = contestID
+	message := fmt.Sprintf("📅 **定期バーチャル「%s」** を作成しました。\n", contest.Title) +
+		virtual.FormatCreatedMessage(contest, nil)
+	_, err = s.discord.ChannelMessageSendComplex(template.ChannelID, &discordgo.MessageSend{
+		Content:    message,
+		Components: virtual.ParticipationButtons(contestID),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Created virtual contest %d from template %d", contestID, template.ID)
+	return nil
+}
+
+// pickRecurringProblems picks problems from a template's difficulty range that no registered
+// member of the server has solved. It returns nil if there are not enough candidates.
+func (s *Scheduler) pickRecurringProblems(template *models.VirtualContestTemplate) ([]*models.Problem, error) {
+	users, err := queries.GetAllUsers(s.db)
+	if err != nil {
+		return nil, err
+	}
+	var memberIDs []string
+	for _, user := range users {
+		if s.isServerMember(template.ServerID, user.DiscordID) {
+			memberIDs = append(memberIDs, user.DiscordID)
+		}
+	}
+
+	minDiff, maxDiff := int(template.DifficultyMin.Int64), int(template.DifficultyMax.Int64)
+	count := int(template.ProblemCount.Int64)
+	candidates, err := queries.GetProblemsUnsolvedByAll(s.db, memberIDs, minDiff, maxDiff)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) < count {
+		return nil, nil
+	}
+	return virtual.PickIncreasingDifficulty(candidates, count, minDiff, maxDiff), nil
+}
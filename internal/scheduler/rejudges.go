@@ -0,0 +1,87 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"coding-winner/internal/atcoder"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// pendingRecheckWindow is how far back submissions still being judged are re-fetched
+const pendingRecheckWindow = 7 * 24 * time.Hour
+
+// recheckPendingSubmissions re-fetches users' submissions that were synced while still being
+// judged. The incremental sync only fetches from the latest submission on, so without this
+// a submission synced as WJ would keep that result once a later submission exists.
+func (s *Scheduler) recheckPendingSubmissions() error {
+	users, err := queries.GetUsersWithPendingSubmissions(s.db, atcoder.FinalResults, time.Now().Add(-pendingRecheckWindow))
+	if err != nil {
+		return err
+	}
+
+	var changes []*models.SubmissionResultChange
+	for _, user := range users {
+		submissions, err := s.atcoderClient.SyncUserSubmissions(user.AtCoderUsername, user.UserID, &user.OldestPending)
+		if err != nil {
+			log.Printf("Error re-checking submissions for %s: %v", user.AtCoderUsername, err)
+			continue
+		}
+
+		changed, err := queries.CreateSubmissions(s.db, submissions)
+		if err != nil {
+			log.Printf("Error saving re-checked submissions for %s: %v", user.AtCoderUsername, err)
+			continue
+		}
+		changes = append(changes, changed...)
+
+		s.atcoderClient.RateLimitDelay()
+	}
+
+	s.applySubmissionResultChanges(changes)
+	return nil
+}
+
+// applySubmissionResultChanges brings features built on synced results up to date after
+// results changed. Running virtual contests, goals and badges (including streaks) are
+// recomputed from the submissions by the rest of the sync run; virtual contests whose
+// final results were already posted are recomputed here and their channel is told.
+func (s *Scheduler) applySubmissionResultChanges(changes []*models.SubmissionResultChange) {
+	if len(changes) == 0 {
+		return
+	}
+	log.Printf("%d submission results changed", len(changes))
+
+	affected := make(map[int]*models.VirtualContest)
+	for _, change := range changes {
+		contests, err := queries.GetPostedVirtualContestsForSubmission(s.db, change.Submission)
+		if err != nil {
+			log.Printf("Error finding virtual contests for submission %d: %v", change.Submission.ID, err)
+			continue
+		}
+		for _, contest := range contests {
+			affected[contest.ID] = contest
+		}
+	}
+
+	for _, contest := range affected {
+		if _, _, err := s.saveVirtualContestResults(contest); err != nil {
+			log.Printf("Error recomputing results for virtual contest %d: %v", contest.ID, err)
+			continue
+		}
+		standings, err := virtual.GetStandings(s.db, contest)
+		if err != nil {
+			log.Printf("Error getting standings for virtual contest %d: %v", contest.ID, err)
+			continue
+		}
+
+		message := fmt.Sprintf("🔁 リジャッジ・判定の確定により「%s」の結果が更新されました（レーティングは変わりません）。\n%s",
+			contest.Title, virtual.FormatStandings(contest, standings))
+		if _, err := s.discord.ChannelMessageSend(contest.ChannelID, message); err != nil {
+			log.Printf("Error announcing updated results for virtual contest %d: %v", contest.ID, err)
+		}
+	}
+}
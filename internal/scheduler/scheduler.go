@@ -2,6 +2,7 @@ package scheduler
 
 import (
 	"log"
+	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/robfig/cron/v3"
@@ -9,21 +10,32 @@ import (
 	"coding-winner/internal/database"
 )
 
+// Submission sync modes
+const (
+	SyncModeUser   = "user"   // poll each user's submissions
+	SyncModeGlobal = "global" // follow the feed of everyone's recent submissions
+)
+
 // Scheduler manages periodic tasks
 type Scheduler struct {
 	cron          *cron.Cron
 	db            *database.DB
 	discord       *discordgo.Session
 	atcoderClient *atcoder.Client
+	syncMode      string
 }
 
-// New creates a new scheduler
-func New(db *database.DB, discord *discordgo.Session, atcoderClient *atcoder.Client) *Scheduler {
+// New creates a new scheduler. An unknown sync mode falls back to per-user polling.
+func New(db *database.DB, discord *discordgo.Session, atcoderClient *atcoder.Client, syncMode string) *Scheduler {
+	if syncMode != SyncModeGlobal {
+		syncMode = SyncModeUser
+	}
 	return &Scheduler{
 		cron:          cron.New(),
 		db:            db,
 		discord:       discord,
 		atcoderClient: atcoderClient,
+		syncMode:      syncMode,
 	}
 }
 
@@ -34,9 +46,22 @@ func (s *Scheduler) Start() error {
 	// Sync submissions every 15 minutes
 	_, err := s.cron.AddFunc("*/15 * * * *", func() {
 		log.Println("Running submission sync...")
+		syncStartedAt := time.Now()
 		if err := s.syncSubmissions(); err != nil {
 			log.Printf("Error syncing submissions: %v", err)
 		}
+		if err := s.recheckPendingSubmissions(); err != nil {
+			log.Printf("Error re-checking pending submissions: %v", err)
+		}
+		if err := s.syncVirtualContests(syncStartedAt); err != nil {
+			log.Printf("Error syncing virtual contests: %v", err)
+		}
+		if err := s.checkGoals(); err != nil {
+			log.Printf("Error checking goals: %v", err)
+		}
+		if err := s.checkAchievements(); err != nil {
+			log.Printf("Error checking achievements: %v", err)
+		}
 	})
 	if err != nil {
 		return err
@@ -53,6 +78,22 @@ func (s *Scheduler) Start() error {
 		return err
 	}
 
+	// Create recurring occurrences and close polls, then start and finish virtual contests every minute
+	_, err = s.cron.AddFunc("* * * * *", func() {
+		if err := s.instantiateRecurringVirtualContests(); err != nil {
+			log.Printf("Error creating recurring virtual contests: %v", err)
+		}
+		if err := s.closeVirtualPolls(); err != nil {
+			log.Printf("Error closing virtual polls: %v", err)
+		}
+		if err := s.updateVirtualContestStates(); err != nil {
+			log.Printf("Error updating virtual contest states: %v", err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
 	// Send weekly reports every Monday at 7:00 AM
 	_, err = s.cron.AddFunc("0 7 * * 1", func() {
 		log.Println("Sending weekly reports...")
@@ -75,17 +116,35 @@ func (s *Scheduler) Start() error {
 		return err
 	}
 
-	// Sync problems daily at 3:00 AM
+	// Sync problems and contests daily at 3:00 AM
 	_, err = s.cron.AddFunc("0 3 * * *", func() {
 		log.Println("Syncing problems...")
 		if err := s.syncProblems(); err != nil {
 			log.Printf("Error syncing problems: %v", err)
 		}
+		if err := s.syncContests(); err != nil {
+			log.Printf("Error syncing contests: %v", err)
+		}
 	})
 	if err != nil {
 		return err
 	}
 
+	// Resume unfinished submission backfills every 10 minutes and right away
+	_, err = s.cron.AddFunc("*/10 * * * *", func() {
+		if err := s.resumeBackfills(); err != nil {
+			log.Printf("Error resuming submission backfills: %v", err)
+		}
+	})
+	if err != nil {
+		return err
+	}
+	go func() {
+		if err := s.resumeBackfills(); err != nil {
+			log.Printf("Error resuming submission backfills: %v", err)
+		}
+	}()
+
 	s.cron.Start()
 	log.Println("Scheduler started successfully")
 	return nil
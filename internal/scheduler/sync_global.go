@@ -0,0 +1,85 @@
+package scheduler
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"coding-winner/internal/atcoder"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// globalSubmissionsCursor names the cursor of the global recent-submissions feed
+const globalSubmissionsCursor = "global_submissions"
+
+// maxGlobalPages caps the pages read in one run so a long outage is caught up over several runs
+const maxGlobalPages = 100
+
+// syncGlobalSubmissions follows the time-ordered feed of everyone's submissions and keeps those
+// of registered users. One request covers every user, so the sync time does not grow with
+// membership. The feed position is saved after each page. Submissions from before a user
+// registered come from the backfill, which still polls per user.
+func (s *Scheduler) syncGlobalSubmissions() error {
+	users, err := queries.GetAllUsers(s.db)
+	if err != nil {
+		return err
+	}
+	// Usernames are case-insensitive on AtCoder
+	byUsername := make(map[string]*models.User, len(users))
+	for _, user := range users {
+		byUsername[strings.ToLower(user.AtCoderUsername)] = user
+	}
+
+	fromSecond, ok, err := queries.GetSyncCursor(s.db, globalSubmissionsCursor)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// Start where the synced data ends, or now on a fresh install
+		fromSecond = time.Now().Unix()
+		latest, err := queries.GetLatestSubmissionTimeOverall(s.db)
+		if err != nil {
+			return err
+		}
+		if latest != nil {
+			fromSecond = latest.Unix()
+		}
+	}
+
+	var changes []*models.SubmissionResultChange
+	synced := 0
+	for page := 0; page < maxGlobalPages; page++ {
+		recent, err := s.atcoderClient.GetRecentSubmissions(fromSecond)
+		if err != nil {
+			return err
+		}
+
+		var submissions []*models.Submission
+		for _, sub := range recent {
+			if user, ok := byUsername[strings.ToLower(sub.UserID)]; ok {
+				submissions = append(submissions, atcoder.ConvertSubmissionToModel(sub, user.DiscordID))
+			}
+		}
+		changed, err := queries.CreateSubmissions(s.db, submissions)
+		if err != nil {
+			return err
+		}
+		changes = append(changes, changed...)
+		synced += len(submissions)
+
+		fromSecond = atcoder.NextFromSecond(fromSecond, recent, atcoder.RecentSubmissionsPageSize)
+		if err := queries.SaveSyncCursor(s.db, globalSubmissionsCursor, fromSecond); err != nil {
+			return err
+		}
+
+		if len(recent) < atcoder.RecentSubmissionsPageSize {
+			break
+		}
+		s.atcoderClient.RateLimitDelay()
+	}
+
+	log.Printf("Synced %d submissions of registered users from the global feed", synced)
+	s.applySubmissionResultChanges(changes)
+	return nil
+}
@@ -1,14 +1,28 @@
 package scheduler
 
 import (
+	"database/sql"
 	"log"
 	"time"
 
+	"coding-winner/internal/atcoder"
+	"coding-winner/internal/backfill"
 	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
 )
 
 // syncSubmissions syncs submissions for all registered users
 func (s *Scheduler) syncSubmissions() error {
+	if s.syncMode == SyncModeGlobal {
+		if err := s.syncGlobalSubmissions(); err != nil {
+			if recordErr := queries.RecordAllUsersSyncFailure(s.db, err.Error()); recordErr != nil {
+				log.Printf("Error recording sync failure: %v", recordErr)
+			}
+			return err
+		}
+		return queries.RecordAllUsersSyncSuccess(s.db)
+	}
+
 	// Get all users
 	users, err := queries.GetAllUsers(s.db)
 	if err != nil {
@@ -17,35 +31,54 @@ func (s *Scheduler) syncSubmissions() error {
 
 	log.Printf("Syncing submissions for %d users", len(users))
 
+	var changes []*models.SubmissionResultChange
+
 	for _, user := range users {
-		// Get latest submission time
-		latestTime, err := queries.GetLatestSubmissionTime(s.db, user.DiscordID)
+		// Users still being backfilled are synced by the backfill
+		pending, err := backfill.IsPending(s.db, user)
 		if err != nil {
-			log.Printf("Error getting latest submission time for %s: %v", user.AtCoderUsername, err)
+			log.Printf("Error getting backfill state for %s: %v", user.AtCoderUsername, err)
+			continue
+		}
+		if pending {
 			continue
 		}
 
-		// Sync submissions
-		var since *time.Time
-		if latestTime != nil {
-			since = latestTime
+		fromSecond, err := s.userSyncCursor(user)
+		if err != nil {
+			log.Printf("Error getting sync cursor for %s: %v", user.AtCoderUsername, err)
+			continue
 		}
 
-		submissions, err := s.atcoderClient.SyncUserSubmissions(user.AtCoderUsername, user.DiscordID, since)
+		// Sync submissions
+		since := time.Unix(fromSecond, 0)
+		submissions, err := s.atcoderClient.SyncUserSubmissions(user.AtCoderUsername, user.DiscordID, &since)
 		if err != nil {
 			log.Printf("Error syncing submissions for %s: %v", user.AtCoderUsername, err)
+			if err := queries.RecordUserSyncFailure(s.db, user.DiscordID, err.Error()); err != nil {
+				log.Printf("Error recording sync failure for %s: %v", user.AtCoderUsername, err)
+			}
 			continue
 		}
 
 		if len(submissions) == 0 {
+			if err := queries.RecordUserSyncSuccess(s.db, user.DiscordID, fromSecond); err != nil {
+				log.Printf("Error recording sync for %s: %v", user.AtCoderUsername, err)
+			}
 			continue
 		}
 
 		// Save submissions
-		if err := queries.CreateSubmissions(s.db, submissions); err != nil {
+		changed, err := queries.CreateSubmissions(s.db, submissions)
+		if err != nil {
 			log.Printf("Error saving submissions for %s: %v", user.AtCoderUsername, err)
 			continue
 		}
+		changes = append(changes, changed...)
+
+		if err := queries.RecordUserSyncSuccess(s.db, user.DiscordID, nextUserSyncEpoch(fromSecond, submissions)); err != nil {
+			log.Printf("Error recording sync for %s: %v", user.AtCoderUsername, err)
+		}
 
 		log.Printf("Synced %d new submissions for %s", len(submissions), user.AtCoderUsername)
 
@@ -53,6 +86,74 @@ func (s *Scheduler) syncSubmissions() error {
 		s.atcoderClient.RateLimitDelay()
 	}
 
+	s.applySubmissionResultChanges(changes)
+	return nil
+}
+
+// userSyncCursor returns the second a user's next sync starts at. Users without a saved
+// cursor start at their newest synced submission.
+func (s *Scheduler) userSyncCursor(user *models.User) (int64, error) {
+	state, err := queries.GetUserSyncState(s.db, user.DiscordID)
+	if err != nil && err != sql.ErrNoRows {
+		return 0, err
+	}
+	if state != nil && state.LastSyncedEpoch.Valid {
+		return state.LastSyncedEpoch.Int64, nil
+	}
+
+	latestTime, err := queries.GetLatestSubmissionTime(s.db, user.DiscordID)
+	if err != nil {
+		return 0, err
+	}
+	if latestTime == nil {
+		return 0, nil
+	}
+	return latestTime.Unix(), nil
+}
+
+// nextUserSyncEpoch returns the cursor after a sync that fetched submissions from fromSecond.
+// After a partial page everything up to its newest second is synced, so the next sync starts
+// one second later. A full page may have more submissions in its last second, so the next
+// sync starts at that second and the duplicates are ignored when saved.
+func nextUserSyncEpoch(fromSecond int64, submissions []*models.Submission) int64 {
+	next := fromSecond
+	for _, sub := range submissions {
+		if epoch := sub.SubmittedAt.Unix(); epoch > next {
+			next = epoch
+		}
+	}
+	if len(submissions) < atcoder.SubmissionsPageSize {
+		return next + 1
+	}
+	if next == fromSecond {
+		next++
+	}
+	return next
+}
+
+// resumeBackfills continues the submission history backfill of every user whose backfill
+// has not finished, including users registered before backfills existed
+func (s *Scheduler) resumeBackfills() error {
+	users, err := queries.GetUsersWithPendingBackfill(s.db)
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		started, err := backfill.Run(s.db, s.atcoderClient, user, nil)
+		if err != nil {
+			log.Printf("Error backfilling submissions for %s: %v", user.AtCoderUsername, err)
+			if err := queries.RecordUserSyncFailure(s.db, user.DiscordID, err.Error()); err != nil {
+				log.Printf("Error recording sync failure for %s: %v", user.AtCoderUsername, err)
+			}
+			continue
+		}
+		if started {
+			log.Printf("Finished submission backfill for %s", user.AtCoderUsername)
+		}
+		s.atcoderClient.RateLimitDelay()
+	}
+
 	return nil
 }
 
@@ -72,3 +173,23 @@ func (s *Scheduler) syncProblems() error {
 	log.Printf("Synced %d problems", len(problems))
 	return nil
 }
+
+// syncContests syncs all contests and their problem sets from AtCoder
+func (s *Scheduler) syncContests() error {
+	log.Println("Syncing contests from AtCoder...")
+
+	contests, contestProblems, err := s.atcoderClient.SyncContests()
+	if err != nil {
+		return err
+	}
+
+	if err := queries.UpsertContests(s.db, contests); err != nil {
+		return err
+	}
+	if err := queries.UpsertContestProblems(s.db, contestProblems); err != nil {
+		return err
+	}
+
+	log.Printf("Synced %d contests", len(contests))
+	return nil
+}
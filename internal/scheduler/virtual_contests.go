@@ -0,0 +1,243 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// syncVirtualContests maps synced submissions into running virtual contests, updates their
+// live standings and feed, and posts final results for contests that ended before syncStartedAt
+func (s *Scheduler) syncVirtualContests(syncStartedAt time.Time) error {
+	contests, err := queries.GetVirtualContestsToSync(s.db)
+	if err != nil {
+		return err
+	}
+
+	if len(contests) == 0 {
+		return nil
+	}
+
+	for _, contest := range contests {
+		participants, solved, err := s.saveVirtualContestResults(contest)
+		if err != nil {
+			log.Printf("Error saving results for virtual contest %d: %v", contest.ID, err)
+			continue
+		}
+		for _, vcs := range solved {
+			if err := virtual.AnnounceSolve(s.discord, contest, participants[vcs.UserID], vcs); err != nil {
+				log.Printf("Error announcing solve in virtual contest %d: %v", contest.ID, err)
+			}
+		}
+
+		standings, err := virtual.GetStandings(s.db, contest)
+		if err != nil {
+			log.Printf("Error getting standings for virtual contest %d: %v", contest.ID, err)
+			continue
+		}
+		if err := virtual.UpdateLiveStandings(s.discord, contest, standings); err != nil {
+			log.Printf("Error updating live standings for virtual contest %d: %v", contest.ID, err)
+		}
+
+		// Final results are posted once a sync has covered the whole contest
+		if contest.Status == models.VirtualContestFinished && virtual.EndTime(contest).Before(syncStartedAt) {
+			embed := virtual.BuildFinalResultsEmbed(contest, standings)
+			if contest.ReplayContestID.Valid && len(standings) > 0 {
+				performances, err := virtual.EstimatePerformances(s.db, contest, standings)
+				if err != nil {
+					log.Printf("Error estimating performances for virtual contest %d: %v", contest.ID, err)
+				} else {
+					embed.Fields = append(embed.Fields, virtual.BuildPerformanceField(standings, performances))
+				}
+			}
+			changes, err := virtual.ApplyRatings(s.db, contest, standings)
+			if err != nil {
+				log.Printf("Error updating ratings for virtual contest %d: %v", contest.ID, err)
+			} else if len(changes) > 0 {
+				embed.Fields = append(embed.Fields, virtual.BuildRatingField(changes))
+			}
+			if _, err := s.discord.ChannelMessageSendEmbed(contest.ChannelID, embed); err != nil {
+				log.Printf("Error posting final results for virtual contest %d: %v", contest.ID, err)
+				continue
+			}
+			if err := queries.MarkVirtualContestResultsPosted(s.db, contest.ID, time.Now()); err != nil {
+				log.Printf("Error marking results posted for virtual contest %d: %v", contest.ID, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// saveVirtualContestResults recomputes a contest's per-problem results from the synced
+// submissions of its participants. It returns the participants by user ID and the results
+// that became AC.
+func (s *Scheduler) saveVirtualContestResults(contest *models.VirtualContest) (map[string]*models.VirtualContestParticipantRow, []*models.VirtualContestSubmission, error) {
+	participants, err := queries.GetVirtualContestParticipants(s.db, contest.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	byUser := make(map[string]*models.VirtualContestParticipantRow, len(participants))
+	participantIDs := make([]string, 0, len(participants))
+	for _, p := range participants {
+		byUser[p.UserID] = p
+		participantIDs = append(participantIDs, p.UserID)
+	}
+
+	// Remember previous results to detect new ACs for the feed
+	previous, err := queries.GetVirtualContestResults(s.db, contest.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	solvedBefore := make(map[string]bool)
+	for _, row := range previous {
+		if row.Result == "AC" {
+			solvedBefore[row.UserID+"/"+row.ProblemID] = true
+		}
+	}
+
+	submissions, err := queries.GetSubmissionsForProblems(s.db, participantIDs, contest.ProblemIDs,
+		contest.StartTime.Time, virtual.EndTime(contest))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Late joiners only count submissions made after they joined
+	var counted []*models.Submission
+	for _, sub := range submissions {
+		if !sub.SubmittedAt.Before(virtual.ParticipantStart(contest, &byUser[sub.UserID].VirtualContestParticipant)) {
+			counted = append(counted, sub)
+		}
+	}
+
+	var solved []*models.VirtualContestSubmission
+	for _, vcs := range aggregateVirtualSubmissions(contest.ID, counted) {
+		if err := queries.CreateVirtualContestSubmission(s.db, vcs); err != nil {
+			log.Printf("Error saving virtual contest submission for contest %d: %v", contest.ID, err)
+			continue
+		}
+		if vcs.Result == "AC" && !solvedBefore[vcs.UserID+"/"+vcs.ProblemID] {
+			solved = append(solved, vcs)
+		}
+	}
+	return byUser, solved, nil
+}
+
+// wrongAttemptResults are the final verdicts that count as a wrong attempt
+var wrongAttemptResults = map[string]bool{
+	"WA": true, "TLE": true, "MLE": true, "RE": true, "OLE": true, "IE": true, "QLE": true,
+}
+
+// aggregateVirtualSubmissions folds submissions (oldest first) into one result per user and problem.
+// Once a problem is solved, later submissions are ignored. Submissions still being judged
+// (WJ, WR, "3/10", ...) are skipped until their verdict is synced, and compile errors do
+// not count as wrong attempts.
+func aggregateVirtualSubmissions(contestID int, submissions []*models.Submission) []*models.VirtualContestSubmission {
+	type key struct {
+		userID    string
+		problemID string
+	}
+
+	results := make(map[key]*models.VirtualContestSubmission)
+	var order []key
+	for _, sub := range submissions {
+		if sub.Result != "AC" && sub.Result != "CE" && !wrongAttemptResults[sub.Result] {
+			continue
+		}
+
+		k := key{sub.UserID, sub.ProblemID}
+		vcs, ok := results[k]
+		if !ok {
+			vcs = &models.VirtualContestSubmission{
+				ContestID: contestID,
+				UserID:    sub.UserID,
+				ProblemID: sub.ProblemID,
+			}
+			results[k] = vcs
+			order = append(order, k)
+		}
+
+		if vcs.Result == "AC" {
+			continue
+		}
+
+		vcs.SubmittedAt = sub.SubmittedAt
+		vcs.Result = sub.Result
+		vcs.Point = sub.Point
+		if wrongAttemptResults[sub.Result] {
+			vcs.WrongAttempts++
+		}
+	}
+
+	aggregated := make([]*models.VirtualContestSubmission, 0, len(order))
+	for _, k := range order {
+		aggregated = append(aggregated, results[k])
+	}
+	return aggregated
+}
+
+// updateVirtualContestStates starts scheduled contests and finishes expired ones, announcing each transition
+func (s *Scheduler) updateVirtualContestStates() error {
+	now := time.Now()
+
+	toStart, err := queries.GetVirtualContestsToStart(s.db, now)
+	if err != nil {
+		return err
+	}
+	for _, contest := range toStart {
+		ok, err := queries.UpdateVirtualContestStatus(s.db, contest.ID, models.VirtualContestScheduled, models.VirtualContestRunning)
+		if err != nil {
+			log.Printf("Error starting virtual contest %d: %v", contest.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		contest.Status = models.VirtualContestRunning
+		problems, err := virtual.LoadProblems(s.db, contest)
+		if err != nil {
+			log.Printf("Error loading problems of virtual contest %d: %v", contest.ID, err)
+			continue
+		}
+		if _, err := s.discord.ChannelMessageSendComplex(contest.ChannelID, &discordgo.MessageSend{
+			Content:    virtual.FormatStartMessage(contest, problems),
+			Components: virtual.ParticipationButtons(contest.ID),
+		}); err != nil {
+			log.Printf("Error announcing start of virtual contest %d: %v", contest.ID, err)
+		}
+		if err := virtual.PostLiveStandings(s.discord, s.db, contest); err != nil {
+			log.Printf("Error posting live standings for virtual contest %d: %v", contest.ID, err)
+		}
+		log.Printf("Started virtual contest %d", contest.ID)
+	}
+
+	toFinish, err := queries.GetVirtualContestsToFinish(s.db, now)
+	if err != nil {
+		return err
+	}
+	for _, contest := range toFinish {
+		ok, err := queries.UpdateVirtualContestStatus(s.db, contest.ID, models.VirtualContestRunning, models.VirtualContestFinished)
+		if err != nil {
+			log.Printf("Error finishing virtual contest %d: %v", contest.ID, err)
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		message := fmt.Sprintf("🏁 **バーチャルコンテスト「%s」終了！** お疲れさまでした。\n"+
+			"最終結果は次回の提出同期後にお知らせします。", contest.Title)
+		if _, err := s.discord.ChannelMessageSend(contest.ChannelID, message); err != nil {
+			log.Printf("Error announcing end of virtual contest %d: %v", contest.ID, err)
+		}
+		log.Printf("Finished virtual contest %d", contest.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"coding-winner/internal/models"
+)
+
+func TestAggregateVirtualSubmissions(t *testing.T) {
+	base := time.Unix(1704542400, 0)
+	sub := func(minute int, result string) *models.Submission {
+		return &models.Submission{
+			UserID:      "u1",
+			ProblemID:   "abc300_a",
+			Result:      result,
+			SubmittedAt: base.Add(time.Duration(minute) * time.Minute),
+		}
+	}
+
+	tests := []struct {
+		name      string
+		results   []string
+		want      string
+		wantWrong int
+		wantAt    int
+	}{
+		{"single AC", []string{"AC"}, "AC", 0, 0},
+		{"final wrong verdicts", []string{"WA", "TLE", "MLE", "RE", "OLE", "IE", "QLE", "AC"}, "AC", 7, 7},
+		{"compile error is free", []string{"CE", "AC"}, "AC", 0, 1},
+		{"pending states are not penalties", []string{"WJ", "WR", "3/10", "WJ 5/12", "Judging", "AC"}, "AC", 0, 5},
+		{"pending does not overwrite verdict", []string{"WA", "3/10"}, "WA", 1, 0},
+		{"submissions after AC are ignored", []string{"AC", "WA"}, "AC", 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var subs []*models.Submission
+			for i, r := range tt.results {
+				subs = append(subs, sub(i, r))
+			}
+
+			got := aggregateVirtualSubmissions(1, subs)
+			if len(got) != 1 {
+				t.Fatalf("got %d results, want 1", len(got))
+			}
+			if got[0].Result != tt.want {
+				t.Errorf("Result = %q, want %q", got[0].Result, tt.want)
+			}
+			if got[0].WrongAttempts != tt.wantWrong {
+				t.Errorf("WrongAttempts = %d, want %d", got[0].WrongAttempts, tt.wantWrong)
+			}
+			if want := base.Add(time.Duration(tt.wantAt) * time.Minute); !got[0].SubmittedAt.Equal(want) {
+				t.Errorf("SubmittedAt = %v, want %v", got[0].SubmittedAt, want)
+			}
+		})
+	}
+}
+
+func TestAggregateVirtualSubmissionsOnlyPending(t *testing.T) {
+	subs := []*models.Submission{
+		{UserID: "u1", ProblemID: "abc300_a", Result: "WJ"},
+		{UserID: "u1", ProblemID: "abc300_a", Result: "1/10"},
+	}
+	if got := aggregateVirtualSubmissions(1, subs); len(got) != 0 {
+		t.Errorf("got %d results for pending submissions, want 0", len(got))
+	}
+}
@@ -0,0 +1,115 @@
+package scheduler
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+	"coding-winner/internal/virtual"
+)
+
+// closeVirtualPolls closes polls whose deadline has passed and creates a virtual contest
+// from each winning option
+func (s *Scheduler) closeVirtualPolls() error {
+	now := time.Now()
+	polls, err := queries.GetExpiredVirtualPolls(s.db, now)
+	if err != nil {
+		return err
+	}
+
+	for _, poll := range polls {
+		// Claim the poll first so the contest is created only once
+		closed, err := queries.CloseVirtualPoll(s.db, poll.ID)
+		if err != nil {
+			log.Printf("Error closing virtual poll %d: %v", poll.ID, err)
+			continue
+		}
+		if !closed {
+			continue
+		}
+		poll.Closed = true
+
+		if err := s.finishVirtualPoll(poll, now); err != nil {
+			log.Printf("Error finishing virtual poll %d: %v", poll.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// finishVirtualPoll disables the poll's buttons, creates the winning contest and announces it
+func (s *Scheduler) finishVirtualPoll(poll *models.VirtualPoll, now time.Time) error {
+	options, err := queries.GetVirtualPollOptions(s.db, poll.ID)
+	if err != nil {
+		return err
+	}
+
+	if poll.MessageID.Valid {
+		embeds := []*discordgo.MessageEmbed{virtual.BuildPollEmbed(poll, options)}
+		components := virtual.PollButtons(poll, options)
+		_, err := s.discord.ChannelMessageEditComplex(&discordgo.MessageEdit{
+			Channel:    poll.ChannelID,
+			ID:         poll.MessageID.String,
+			Embeds:     embeds,
+			Components: components,
+		})
+		if err != nil {
+			log.Printf("Error closing virtual poll message %d: %v", poll.ID, err)
+		}
+	}
+
+	winner := virtual.PollWinner(options)
+	if winner == nil {
+		_, err := s.discord.ChannelMessageSend(poll.ChannelID, "🗳️ 投票がなかったため、バーチャルコンテストは作成しませんでした。")
+		return err
+	}
+
+	contest := &models.VirtualContest{
+		ServerID:        poll.ServerID,
+		ChannelID:       poll.ChannelID,
+		CreatedBy:       poll.CreatedBy,
+		DurationMinutes: poll.DurationMinutes,
+		Status:          virtual.PollContestStatus(poll, now),
+		PenaltyRule:     poll.PenaltyRule,
+	}
+	if contest.Status == models.VirtualContestScheduled {
+		contest.StartTime = poll.StartTime
+	}
+
+	if winner.ReplayContestID.Valid {
+		if _, err := virtual.SetupReplay(s.db, contest, winner.ReplayContestID.String); err != nil {
+			return err
+		}
+	} else {
+		contest.Title = fmt.Sprintf("投票で決まったバーチャル（%s）", now.In(virtual.JST).Format("01/02"))
+		contest.ProblemIDs = winner.ProblemIDs
+		contest.ProblemPoints = winner.ProblemPoints
+	}
+
+	contestID, err := queries.CreateVirtualContest(s.db, contest)
+	if err != nil {
+		return err
+	}
+	if err := queries.SetVirtualPollContest(s.db, poll.ID, contestID); err != nil {
+		return err
+	}
+
+	contest.ID = contestID
+	message := fmt.Sprintf("🗳️ 投票の結果、**候補%d**（%d票）に決まりました！\n"+
+		"バーチャルコンテスト「%s」を作成しました。\n", winner.OptionIndex+1, winner.Votes, contest.Title) +
+		virtual.FormatCreatedMessage(contest, nil)
+
+	_, err = s.discord.ChannelMessageSendComplex(poll.ChannelID, &discordgo.MessageSend{
+		Content:    message,
+		Components: virtual.ParticipationButtons(contestID),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Created virtual contest %d from poll %d", contestID, poll.ID)
+	return nil
+}
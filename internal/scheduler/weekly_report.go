@@ -8,6 +8,7 @@ import (
 
 	"github.com/bwmarrin/discordgo"
 	"coding-winner/internal/database/queries"
+	"coding-winner/internal/goals"
 	"coding-winner/internal/models"
 )
 
@@ -45,7 +46,15 @@ func (s *Scheduler) sendWeeklyReports() error {
 	// Send reports to each configured channel
 	for _, config := range configs {
 		embed := buildWeeklyReportEmbed(stats, lastMonday, thisMonday)
-		_, err := s.discord.ChannelMessageSendEmbed(config.ChannelID, embed)
+
+		completedGoals, err := queries.GetCompletedGoals(s.db, config.ServerID, lastMonday, thisMonday)
+		if err != nil {
+			log.Printf("Error getting completed goals for server %s: %v", config.ServerID, err)
+		} else if len(completedGoals) > 0 {
+			embed.Fields = append(embed.Fields, buildGoalCompletionsField(completedGoals))
+		}
+
+		_, err = s.discord.ChannelMessageSendEmbed(config.ChannelID, embed)
 		if err != nil {
 			log.Printf("Error sending weekly report to channel %s: %v", config.ChannelID, err)
 			continue
@@ -57,6 +66,24 @@ func (s *Scheduler) sendWeeklyReports() error {
 	return nil
 }
 
+// buildGoalCompletionsField builds an embed field listing goal completions
+func buildGoalCompletionsField(completedGoals []*models.Goal) *discordgo.MessageEmbedField {
+	var sb strings.Builder
+	for i, goal := range completedGoals {
+		if i >= 15 {
+			sb.WriteString(fmt.Sprintf("…ほか%d件\n", len(completedGoals)-i))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("🎯 <@%s>: %s\n", goal.UserID, goals.Describe(goal)))
+	}
+
+	return &discordgo.MessageEmbedField{
+		Name:   "目標達成",
+		Value:  sb.String(),
+		Inline: false,
+	}
+}
+
 // buildWeeklyReportEmbed builds an embed for the weekly report
 func buildWeeklyReportEmbed(stats []models.WeeklyStats, startTime, endTime time.Time) *discordgo.MessageEmbed {
 	embed := &discordgo.MessageEmbed{
@@ -0,0 +1,126 @@
+package virtual
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+
+	"coding-winner/internal/models"
+)
+
+// pointSteps maps difficulty upper bounds to AtCoder-style point values
+var pointSteps = []struct {
+	maxDifficulty int
+	point         float64
+}{
+	{200, 100},
+	{600, 200},
+	{1000, 300},
+	{1400, 400},
+	{1800, 500},
+	{2200, 600},
+	{2600, 700},
+}
+
+// ParseDifficultyRange parses a range such as "400-1600"
+func ParseDifficultyRange(value string) (int, int, error) {
+	parts := strings.SplitN(strings.TrimSpace(value), "-", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid difficulty range: %s", value)
+	}
+
+	min, err := strconv.Atoi(strings.TrimSpace(parts[0]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid difficulty range: %s", value)
+	}
+	max, err := strconv.Atoi(strings.TrimSpace(parts[1]))
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid difficulty range: %s", value)
+	}
+	if min > max {
+		return 0, 0, fmt.Errorf("invalid difficulty range: %s", value)
+	}
+	return min, max, nil
+}
+
+// PickIncreasingDifficulty picks count problems of increasing difficulty from candidates.
+// The range is split into count equal bands and one random problem is taken from each band;
+// bands without candidates are filled from the remaining problems closest to the band.
+func PickIncreasingDifficulty(candidates []*models.Problem, count, minDiff, maxDiff int) []*models.Problem {
+	if len(candidates) <= count {
+		picked := append([]*models.Problem(nil), candidates...)
+		sortByDifficulty(picked)
+		return picked
+	}
+
+	used := make(map[string]bool)
+	var picked []*models.Problem
+	width := float64(maxDiff-minDiff+1) / float64(count)
+	for band := 0; band < count; band++ {
+		low := minDiff + int(float64(band)*width)
+		high := minDiff + int(float64(band+1)*width)
+		center := (low + high) / 2
+
+		var inBand []*models.Problem
+		var closest *models.Problem
+		for _, p := range candidates {
+			if used[p.ProblemID] {
+				continue
+			}
+			d := int(p.Difficulty.Int64)
+			if d >= low && d < high {
+				inBand = append(inBand, p)
+			}
+			if closest == nil || abs(d-center) < abs(int(closest.Difficulty.Int64)-center) {
+				closest = p
+			}
+		}
+
+		choice := closest
+		if len(inBand) > 0 {
+			choice = inBand[rand.Intn(len(inBand))]
+		}
+		used[choice.ProblemID] = true
+		picked = append(picked, choice)
+	}
+
+	sortByDifficulty(picked)
+	return picked
+}
+
+// PointForDifficulty returns an AtCoder-style point value for a problem difficulty
+func PointForDifficulty(difficulty int) float64 {
+	for _, step := range pointSteps {
+		if difficulty < step.maxDifficulty {
+			return step.point
+		}
+	}
+	return 800
+}
+
+// AutoProblemSet returns the IDs of picked problems with their difficulty-based point values
+func AutoProblemSet(problems []*models.Problem) ([]string, []float64) {
+	ids := make([]string, 0, len(problems))
+	points := make([]float64, 0, len(problems))
+	for _, p := range problems {
+		ids = append(ids, p.ProblemID)
+		points = append(points, PointForDifficulty(int(p.Difficulty.Int64)))
+	}
+	return ids, points
+}
+
+// sortByDifficulty sorts problems by ascending difficulty
+func sortByDifficulty(problems []*models.Problem) {
+	sort.SliceStable(problems, func(a, b int) bool {
+		return problems[a].Difficulty.Int64 < problems[b].Difficulty.Int64
+	})
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
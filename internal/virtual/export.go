@@ -0,0 +1,227 @@
+package virtual
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// TimelineEntry is one counted submission of a participant during a contest
+type TimelineEntry struct {
+	UserID          string    `json:"user_id"`
+	AtCoderUsername string    `json:"atcoder_username"`
+	ProblemID       string    `json:"problem_id"`
+	Label           string    `json:"label"`
+	SubmittedAt     time.Time `json:"submitted_at"`
+	ElapsedSeconds  int       `json:"elapsed_seconds"`
+	Result          string    `json:"result"`
+	Point           float64   `json:"point"`
+}
+
+// exportProblem is the per-problem result of a standing in the JSON export
+type exportProblem struct {
+	ProblemID      string  `json:"problem_id"`
+	Label          string  `json:"label"`
+	Solved         bool    `json:"solved"`
+	ElapsedSeconds *int    `json:"elapsed_seconds,omitempty"`
+	WrongAttempts  int     `json:"wrong_attempts"`
+	Point          float64 `json:"point"`
+	Presolved      bool    `json:"presolved"`
+}
+
+// exportStanding is a row of the standings in the JSON export
+type exportStanding struct {
+	Rank           int             `json:"rank"`
+	ID             string          `json:"id"`
+	Name           string          `json:"name"`
+	Score          float64         `json:"score"`
+	Solved         int             `json:"solved"`
+	PenaltySeconds int             `json:"penalty_seconds"`
+	Problems       []exportProblem `json:"problems"`
+}
+
+// exportContest is the top-level document of the JSON export
+type exportContest struct {
+	ID              int              `json:"id"`
+	Title           string           `json:"title"`
+	Status          string           `json:"status"`
+	PenaltyRule     string           `json:"penalty_rule"`
+	StartTime       *time.Time       `json:"start_time,omitempty"`
+	DurationMinutes int              `json:"duration_minutes"`
+	ProblemIDs      []string         `json:"problem_ids"`
+	Standings       []exportStanding `json:"standings"`
+	Submissions     []TimelineEntry  `json:"submissions"`
+}
+
+// LoadTimeline loads every submission counted for the participants of a contest, oldest first.
+// Like the standings, late joiners only count submissions made after they joined.
+func LoadTimeline(db queries.UserDB, contest *models.VirtualContest) ([]TimelineEntry, error) {
+	if !contest.StartTime.Valid {
+		return nil, nil
+	}
+
+	participants, err := queries.GetVirtualContestParticipants(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	byUser := make(map[string]*models.VirtualContestParticipantRow, len(participants))
+	userIDs := make([]string, 0, len(participants))
+	for _, p := range participants {
+		byUser[p.UserID] = p
+		userIDs = append(userIDs, p.UserID)
+	}
+
+	submissions, err := queries.GetSubmissionsForProblems(db, userIDs, contest.ProblemIDs, contest.StartTime.Time, EndTime(contest))
+	if err != nil {
+		return nil, err
+	}
+
+	labels := make(map[string]string, len(contest.ProblemIDs))
+	for i, pid := range contest.ProblemIDs {
+		labels[pid] = ProblemLabel(i)
+	}
+
+	var timeline []TimelineEntry
+	for _, sub := range submissions {
+		participant := byUser[sub.UserID]
+		start := ParticipantStart(contest, &participant.VirtualContestParticipant)
+		if sub.SubmittedAt.Before(start) {
+			continue
+		}
+		elapsed := sub.SubmittedAt.Sub(start)
+		timeline = append(timeline, TimelineEntry{
+			UserID:          sub.UserID,
+			AtCoderUsername: participant.AtCoderUsername,
+			ProblemID:       sub.ProblemID,
+			Label:           labels[sub.ProblemID],
+			SubmittedAt:     sub.SubmittedAt,
+			ElapsedSeconds:  int(elapsed.Seconds()),
+			Result:          sub.Result,
+			Point:           sub.Point,
+		})
+	}
+	return timeline, nil
+}
+
+// ExportJSON encodes a contest with its standings and submission timeline as JSON
+func ExportJSON(contest *models.VirtualContest, standings []models.VirtualContestStanding, timeline []TimelineEntry) ([]byte, error) {
+	doc := exportContest{
+		ID:              contest.ID,
+		Title:           contest.Title,
+		Status:          contest.Status,
+		PenaltyRule:     contest.PenaltyRule,
+		DurationMinutes: contest.DurationMinutes,
+		ProblemIDs:      contest.ProblemIDs,
+		Standings:       make([]exportStanding, 0, len(standings)),
+		Submissions:     timeline,
+	}
+	if contest.StartTime.Valid {
+		doc.StartTime = &contest.StartTime.Time
+	}
+	if doc.Submissions == nil {
+		doc.Submissions = []TimelineEntry{}
+	}
+
+	for _, standing := range standings {
+		row := exportStanding{
+			Rank:           standing.Rank,
+			ID:             standing.UserID,
+			Name:           standing.AtCoderUsername,
+			Score:          standing.TotalPoints,
+			Solved:         standing.SolvedCount,
+			PenaltySeconds: int(standing.PenaltyTime.Seconds()),
+		}
+		for i, pid := range contest.ProblemIDs {
+			result := standing.Problems[pid]
+			problem := exportProblem{
+				ProblemID:     pid,
+				Label:         ProblemLabel(i),
+				Solved:        result.Solved,
+				WrongAttempts: result.WrongAttempts,
+				Point:         result.Point,
+				Presolved:     result.Presolved,
+			}
+			if result.Solved {
+				seconds := int(result.Elapsed.Seconds())
+				problem.ElapsedSeconds = &seconds
+			}
+			row.Problems = append(row.Problems, problem)
+		}
+		doc.Standings = append(doc.Standings, row)
+	}
+
+	return json.MarshalIndent(doc, "", "  ")
+}
+
+// ExportCSV encodes the standings and the submission timeline of a contest as two CSV files.
+// Standings have one "<label> time" and "<label> wa" column pair per problem, followed by the
+// labels of problems solved before the start.
+func ExportCSV(contest *models.VirtualContest, standings []models.VirtualContestStanding, timeline []TimelineEntry) ([]byte, []byte, error) {
+	var standingsBuf bytes.Buffer
+	w := csv.NewWriter(&standingsBuf)
+	header := []string{"rank", "id", "name", "score", "solved", "penalty_seconds"}
+	for i := range contest.ProblemIDs {
+		label := ProblemLabel(i)
+		header = append(header, label+" time", label+" wa")
+	}
+	header = append(header, "presolved")
+	w.Write(header)
+	for _, standing := range standings {
+		record := []string{
+			strconv.Itoa(standing.Rank),
+			standing.UserID,
+			standing.AtCoderUsername,
+			strconv.FormatFloat(standing.TotalPoints, 'f', -1, 64),
+			strconv.Itoa(standing.SolvedCount),
+			strconv.Itoa(int(standing.PenaltyTime.Seconds())),
+		}
+		for _, pid := range contest.ProblemIDs {
+			result := standing.Problems[pid]
+			elapsed := ""
+			if result.Solved {
+				elapsed = strconv.Itoa(int(result.Elapsed.Seconds()))
+			}
+			record = append(record, elapsed, strconv.Itoa(result.WrongAttempts))
+		}
+		var presolved []string
+		for i, pid := range contest.ProblemIDs {
+			if standing.Problems[pid].Presolved {
+				presolved = append(presolved, ProblemLabel(i))
+			}
+		}
+		record = append(record, strings.Join(presolved, " "))
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, nil, err
+	}
+
+	var timelineBuf bytes.Buffer
+	w = csv.NewWriter(&timelineBuf)
+	w.Write([]string{"submitted_at", "elapsed_seconds", "user_id", "atcoder_username", "label", "problem_id", "result", "point"})
+	for _, entry := range timeline {
+		w.Write([]string{
+			entry.SubmittedAt.Format(time.RFC3339),
+			strconv.Itoa(entry.ElapsedSeconds),
+			entry.UserID,
+			entry.AtCoderUsername,
+			entry.Label,
+			entry.ProblemID,
+			entry.Result,
+			strconv.FormatFloat(entry.Point, 'f', -1, 64),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, nil, err
+	}
+
+	return standingsBuf.Bytes(), timelineBuf.Bytes(), nil
+}
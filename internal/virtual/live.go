@@ -0,0 +1,140 @@
+package virtual
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// threadArchiveMinutes is how long the solve feed thread stays active without messages
+const threadArchiveMinutes = 1440
+
+// PostLiveStandings posts the standings message that is kept up to date while the contest runs,
+// opens a thread on it for the solve feed, and saves both IDs
+func PostLiveStandings(s *discordgo.Session, db queries.UserDB, contest *models.VirtualContest) error {
+	standings, err := GetStandings(db, contest)
+	if err != nil {
+		return err
+	}
+
+	msg, err := s.ChannelMessageSend(contest.ChannelID, formatLiveStandings(contest, standings))
+	if err != nil {
+		return fmt.Errorf("failed to post standings message: %w", err)
+	}
+
+	// The feed thread is optional; standings still work without it
+	threadID := ""
+	thread, err := s.MessageThreadStart(contest.ChannelID, msg.ID, fmt.Sprintf("%s - 実況", contest.Title), threadArchiveMinutes)
+	if err == nil {
+		threadID = thread.ID
+	}
+
+	if err := queries.SetVirtualContestLiveMessage(db, contest.ID, msg.ID, threadID); err != nil {
+		return err
+	}
+	contest.StandingsMessageID.String, contest.StandingsMessageID.Valid = msg.ID, true
+	contest.ThreadID.String, contest.ThreadID.Valid = threadID, threadID != ""
+	return nil
+}
+
+// UpdateLiveStandings edits the live standings message in place
+func UpdateLiveStandings(s *discordgo.Session, contest *models.VirtualContest, standings []models.VirtualContestStanding) error {
+	if !contest.StandingsMessageID.Valid {
+		return nil
+	}
+	_, err := s.ChannelMessageEdit(contest.ChannelID, contest.StandingsMessageID.String, formatLiveStandings(contest, standings))
+	return err
+}
+
+// AnnounceSolve posts a line to the contest's feed thread when a participant solves a problem
+func AnnounceSolve(s *discordgo.Session, contest *models.VirtualContest, participant *models.VirtualContestParticipantRow, sub *models.VirtualContestSubmission) error {
+	if !contest.ThreadID.Valid {
+		return nil
+	}
+
+	label := sub.ProblemID
+	for i, pid := range contest.ProblemIDs {
+		if pid == sub.ProblemID {
+			label = ProblemLabel(i)
+			break
+		}
+	}
+
+	message := fmt.Sprintf("🎈 **%s** が **%s** を解きました（%s, 経過 %s）",
+		participant.AtCoderUsername, label, sub.SubmittedAt.In(JST).Format("15:04"),
+		FormatElapsed(sub.SubmittedAt.Sub(ParticipantStart(contest, &participant.VirtualContestParticipant))))
+	if sub.WrongAttempts > 0 {
+		message += fmt.Sprintf(" 誤答%d回", sub.WrongAttempts)
+	}
+
+	_, err := s.ChannelMessageSend(contest.ThreadID.String, message)
+	return err
+}
+
+// ParticipationButtons returns the Join/Leave buttons attached to contest announcements
+func ParticipationButtons(contestID int) []discordgo.MessageComponent {
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{
+			Components: []discordgo.MessageComponent{
+				discordgo.Button{
+					Label:    "参加する",
+					Style:    discordgo.PrimaryButton,
+					CustomID: fmt.Sprintf("virtual-join:%d", contestID),
+				},
+				discordgo.Button{
+					Label:    "参加を取り消す",
+					Style:    discordgo.SecondaryButton,
+					CustomID: fmt.Sprintf("virtual-leave:%d", contestID),
+				},
+			},
+		},
+	}
+}
+
+// BuildFinalResultsEmbed builds the final results embed with medals for the top three
+func BuildFinalResultsEmbed(contest *models.VirtualContest, standings []models.VirtualContestStanding) *discordgo.MessageEmbed {
+	embed := &discordgo.MessageEmbed{
+		Title:     fmt.Sprintf("🏆 %s - 最終結果", contest.Title),
+		Color:     0xf1c40f,
+		Timestamp: time.Now().Format(time.RFC3339),
+	}
+
+	if len(standings) == 0 {
+		embed.Description = "参加者はいませんでした。"
+		return embed
+	}
+
+	var sb strings.Builder
+	for i, standing := range standings {
+		if i >= 20 {
+			sb.WriteString(fmt.Sprintf("…ほか%d人\n", len(standings)-i))
+			break
+		}
+
+		medal := fmt.Sprintf("%d.", standing.Rank)
+		switch standing.Rank {
+		case 1:
+			medal = "🥇"
+		case 2:
+			medal = "🥈"
+		case 3:
+			medal = "🥉"
+		}
+		sb.WriteString(fmt.Sprintf("%s **%s** - %.0f点 / %d完 (%s)\n",
+			medal, standing.AtCoderUsername, standing.TotalPoints, standing.SolvedCount, FormatElapsed(standing.PenaltyTime)))
+	}
+	embed.Description = sb.String()
+
+	return embed
+}
+
+// formatLiveStandings formats the body of the live standings message
+func formatLiveStandings(contest *models.VirtualContest, standings []models.VirtualContestStanding) string {
+	return fmt.Sprintf("📊 **%s - 順位表**（%s・%s 終了）\n%s",
+		contest.Title, StatusLabel(contest.Status), EndTime(contest).In(JST).Format("15:04"),
+		FormatStandings(contest, standings))
+}
@@ -0,0 +1,102 @@
+package virtual
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/models"
+)
+
+// MaxPollOptions is the number of options that fit in one row of vote buttons
+const MaxPollOptions = 5
+
+// AutoPollOptionLabel describes a generated problem set as one line per problem
+func AutoPollOptionLabel(problems []*models.Problem) string {
+	var lines []string
+	for idx, p := range problems {
+		lines = append(lines, fmt.Sprintf("%s. %s（diff %d）", ProblemLabel(idx), p.Title, p.Difficulty.Int64))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ReplayPollOptionLabel describes a past contest offered for replay
+func ReplayPollOptionLabel(contest *models.Contest) string {
+	return fmt.Sprintf("%s（`%s`・%d分）", contest.Title, contest.ContestID, contest.DurationSeconds/60)
+}
+
+// BuildPollEmbed builds the embed listing a poll's options and their current votes
+func BuildPollEmbed(poll *models.VirtualPoll, options []*models.VirtualPollOption) *discordgo.MessageEmbed {
+	kind := "問題セット"
+	if poll.Mode == models.VirtualPollReplay {
+		kind = "リプレイするコンテスト"
+	}
+
+	embed := &discordgo.MessageEmbed{
+		Title: fmt.Sprintf("🗳️ 次のバーチャルコンテストの%sを投票で決めます", kind),
+		Color: 0x9b59b6,
+	}
+	if poll.Closed {
+		embed.Description = "投票は締め切られました。"
+	} else {
+		embed.Description = fmt.Sprintf("締め切り: %s\n下のボタンで投票してください（投票は何度でも変更できます）。",
+			poll.Deadline.In(JST).Format(StartTimeLayout))
+	}
+
+	for _, opt := range options {
+		embed.Fields = append(embed.Fields, &discordgo.MessageEmbedField{
+			Name:  fmt.Sprintf("候補%d（%d票）", opt.OptionIndex+1, opt.Votes),
+			Value: opt.Label,
+		})
+	}
+
+	footer := ""
+	if poll.Mode == models.VirtualPollAuto {
+		footer = fmt.Sprintf("%d分・", poll.DurationMinutes)
+	}
+	if poll.StartTime.Valid {
+		footer += fmt.Sprintf("%s 開始", poll.StartTime.Time.In(JST).Format(StartTimeLayout))
+	} else {
+		footer += "作成後に /virtual-start で開始"
+	}
+	embed.Footer = &discordgo.MessageEmbedFooter{Text: footer}
+	return embed
+}
+
+// PollButtons returns one vote button per option. Closed polls get disabled buttons.
+func PollButtons(poll *models.VirtualPoll, options []*models.VirtualPollOption) []discordgo.MessageComponent {
+	var buttons []discordgo.MessageComponent
+	for _, opt := range options {
+		buttons = append(buttons, discordgo.Button{
+			Label:    fmt.Sprintf("候補%d", opt.OptionIndex+1),
+			Style:    discordgo.PrimaryButton,
+			CustomID: fmt.Sprintf("virtual-poll:%d:%d", poll.ID, opt.OptionIndex),
+			Disabled: poll.Closed,
+		})
+	}
+	return []discordgo.MessageComponent{
+		discordgo.ActionsRow{Components: buttons},
+	}
+}
+
+// PollWinner returns the option with the most votes, preferring the earlier option on ties.
+// It returns nil if nobody voted.
+func PollWinner(options []*models.VirtualPollOption) *models.VirtualPollOption {
+	var winner *models.VirtualPollOption
+	for _, opt := range options {
+		if opt.Votes > 0 && (winner == nil || opt.Votes > winner.Votes) {
+			winner = opt
+		}
+	}
+	return winner
+}
+
+// PollContestStatus returns the status of a contest created from a poll: scheduled if the
+// poll fixed a start time that is still ahead, otherwise a draft
+func PollContestStatus(poll *models.VirtualPoll, now time.Time) string {
+	if poll.StartTime.Valid && poll.StartTime.Time.After(now) {
+		return models.VirtualContestScheduled
+	}
+	return models.VirtualContestDraft
+}
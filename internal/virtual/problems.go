@@ -0,0 +1,183 @@
+package virtual
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+var (
+	// taskURLPattern matches https://atcoder.jp/contests/<contest>/tasks/<problem>
+	taskURLPattern = regexp.MustCompile(`^https?://atcoder\.jp/contests/([^/\s]+)/tasks/([^/\s?#]+)`)
+	// contestLettersPattern matches "<contest> <letters>" such as "abc300 C-F" or "abc300 ACE"
+	contestLettersPattern = regexp.MustCompile(`^(\S+)\s+([A-Za-z](?:-?[A-Za-z])*)$`)
+)
+
+// ProblemRef is one parsed entry of a problem list
+type ProblemRef struct {
+	Raw       string
+	ProblemID string // set for problem IDs and task URLs
+	ContestID string // set for task URLs and contest+letter entries
+	Letter    string // set for contest+letter entries (upper case)
+}
+
+// ParseProblemRefs parses a comma-separated problem list. Each entry may be a problem ID
+// (abc300_c), a task URL, or a contest ID followed by letters or letter ranges (abc300 C-F).
+func ParseProblemRefs(input string) []ProblemRef {
+	var refs []ProblemRef
+	for _, entry := range strings.Split(input, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		if m := taskURLPattern.FindStringSubmatch(entry); m != nil {
+			refs = append(refs, ProblemRef{Raw: entry, ContestID: m[1], ProblemID: m[2]})
+			continue
+		}
+
+		if m := contestLettersPattern.FindStringSubmatch(entry); m != nil {
+			for _, letter := range expandLetters(strings.ToUpper(m[2])) {
+				refs = append(refs, ProblemRef{Raw: entry, ContestID: m[1], Letter: letter})
+			}
+			continue
+		}
+
+		refs = append(refs, ProblemRef{Raw: entry, ProblemID: entry})
+	}
+	return refs
+}
+
+// expandLetters expands a letter spec such as "C-F" or "ACE" into individual letters
+func expandLetters(spec string) []string {
+	var letters []string
+	for i := 0; i < len(spec); i++ {
+		if i+2 < len(spec) && spec[i+1] == '-' {
+			for c := spec[i]; c <= spec[i+2]; c++ {
+				letters = append(letters, string(c))
+			}
+			i += 2
+			continue
+		}
+		if spec[i] != '-' {
+			letters = append(letters, string(spec[i]))
+		}
+	}
+	return letters
+}
+
+// ResolveProblems resolves a problem list against the problems table.
+// It returns the resolved problem IDs (deduplicated, in input order) and a
+// user-facing message for each entry that could not be resolved.
+func ResolveProblems(db queries.UserDB, input string) ([]string, []string, error) {
+	var problemIDs, unresolved []string
+	seen := make(map[string]bool)
+	contestProblems := make(map[string][]*models.Problem)
+
+	for _, ref := range ParseProblemRefs(input) {
+		var problem *models.Problem
+
+		if ref.Letter != "" {
+			problems, ok := contestProblems[ref.ContestID]
+			if !ok {
+				var err error
+				problems, err = queries.GetContestProblems(db, ref.ContestID)
+				if err != nil {
+					return nil, nil, err
+				}
+				contestProblems[ref.ContestID] = problems
+			}
+			problem = findByLetter(problems, ref.Letter)
+		} else {
+			found, err := queries.GetProblemsByIDs(db, []string{ref.ProblemID})
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(found) > 0 {
+				problem = found[0]
+			}
+		}
+
+		if problem == nil {
+			msg, err := unresolvedMessage(db, ref)
+			if err != nil {
+				return nil, nil, err
+			}
+			unresolved = append(unresolved, msg)
+			continue
+		}
+
+		if !seen[problem.ProblemID] {
+			seen[problem.ProblemID] = true
+			problemIDs = append(problemIDs, problem.ProblemID)
+		}
+	}
+
+	return problemIDs, unresolved, nil
+}
+
+// findByLetter finds the task with the given letter in a contest, by its "C. Title"
+// style title or, failing that, by its problem ID suffix
+func findByLetter(problems []*models.Problem, letter string) *models.Problem {
+	for _, p := range problems {
+		if strings.HasPrefix(p.Title, letter+". ") {
+			return p
+		}
+	}
+	for _, p := range problems {
+		if strings.HasSuffix(p.ProblemID, "_"+strings.ToLower(letter)) {
+			return p
+		}
+	}
+	return nil
+}
+
+// unresolvedMessage explains why an entry could not be resolved, with suggestions if any
+func unresolvedMessage(db queries.UserDB, ref ProblemRef) (string, error) {
+	prefix := ref.ContestID
+	if prefix == "" {
+		prefix = strings.SplitN(ref.ProblemID, "_", 2)[0]
+	}
+
+	suggestions, err := queries.SuggestProblemIDs(db, prefix, 5)
+	if err != nil {
+		return "", err
+	}
+
+	label := ref.Raw
+	if ref.Letter != "" {
+		label = fmt.Sprintf("%s %s", ref.ContestID, ref.Letter)
+	}
+	msg := fmt.Sprintf("`%s` が見つかりません", label)
+	if len(suggestions) > 0 {
+		msg += fmt.Sprintf("（候補: %s）", strings.Join(suggestions, ", "))
+	}
+	return msg, nil
+}
+
+// LoadProblems loads the problems of a contest in contest order.
+// Problems missing from the problems table are returned with only their ID set.
+func LoadProblems(db queries.UserDB, contest *models.VirtualContest) ([]*models.Problem, error) {
+	found, err := queries.GetProblemsByIDs(db, contest.ProblemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*models.Problem, len(found))
+	for _, p := range found {
+		byID[p.ProblemID] = p
+	}
+
+	problems := make([]*models.Problem, len(contest.ProblemIDs))
+	for i, pid := range contest.ProblemIDs {
+		if p, ok := byID[pid]; ok {
+			problems[i] = p
+		} else {
+			problems[i] = &models.Problem{ProblemID: pid, Title: pid}
+		}
+	}
+	return problems, nil
+}
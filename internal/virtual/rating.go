@@ -0,0 +1,144 @@
+package virtual
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/database"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// InitialRating is the rating of a member before their first rated virtual contest
+const InitialRating = 1500
+
+// ratingK is the largest possible rating change in one contest
+const ratingK = 64
+
+// ComputeRatingChanges applies a multiplayer Elo update to the verified members in the standings.
+// Every member is compared with every other member: beating them scores 1, a tie 0.5,
+// and the change is K times the average of (score - expected score). Team standings and
+// unverified members are skipped, and nothing is rated with fewer than two members.
+func ComputeRatingChanges(contest *models.VirtualContest, standings []models.VirtualContestStanding, current map[string]int, verified map[string]bool) []*models.VirtualRatingChange {
+	var rated []models.VirtualContestStanding
+	for _, standing := range standings {
+		if !IsTeamStanding(standing) && verified[standing.UserID] {
+			rated = append(rated, standing)
+		}
+	}
+	if len(rated) < 2 {
+		return nil
+	}
+
+	ratingOf := func(userID string) int {
+		if rating, ok := current[userID]; ok {
+			return rating
+		}
+		return InitialRating
+	}
+
+	var changes []*models.VirtualRatingChange
+	for _, me := range rated {
+		myRating := ratingOf(me.UserID)
+		delta := 0.0
+		for _, other := range rated {
+			if other.UserID == me.UserID {
+				continue
+			}
+			expected := 1 / (1 + math.Pow(10, float64(ratingOf(other.UserID)-myRating)/400))
+			score := 0.5
+			if me.Rank < other.Rank {
+				score = 1
+			} else if me.Rank > other.Rank {
+				score = 0
+			}
+			delta += score - expected
+		}
+
+		changes = append(changes, &models.VirtualRatingChange{
+			ContestID:   contest.ID,
+			UserID:      me.UserID,
+			ServerID:    contest.ServerID,
+			ContestRank: me.Rank,
+			OldRating:   myRating,
+			NewRating:   myRating + int(math.Round(ratingK*delta/float64(len(rated)-1))),
+		})
+	}
+	return changes
+}
+
+// ApplyRatings rates a finished contest and returns its rating changes. A contest that was
+// already rated is not rated again, so this is safe to call whenever results are posted.
+// All changes of a contest are saved in one transaction, so a contest is never partly rated.
+func ApplyRatings(db *database.DB, contest *models.VirtualContest, standings []models.VirtualContestStanding) ([]*models.VirtualRatingChangeRow, error) {
+	existing, err := queries.GetVirtualContestRatingChanges(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) > 0 {
+		return existing, nil
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	ratings, err := queries.GetServerVirtualRatings(tx, contest.ServerID)
+	if err != nil {
+		return nil, err
+	}
+	current := make(map[string]int)
+	for _, r := range ratings {
+		current[r.UserID] = r.Rating
+	}
+
+	userIDs := make([]string, 0, len(standings))
+	for _, standing := range standings {
+		userIDs = append(userIDs, standing.UserID)
+	}
+	verified, err := queries.GetVerifiedUserIDs(tx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, change := range ComputeRatingChanges(contest, standings, current, verified) {
+		if _, err := queries.SaveVirtualRatingChange(tx, change); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return queries.GetVirtualContestRatingChanges(db, contest.ID)
+}
+
+// BuildRatingField builds the embed field summarising the rating changes of a contest
+func BuildRatingField(changes []*models.VirtualRatingChangeRow) *discordgo.MessageEmbedField {
+	var sb strings.Builder
+	for i, change := range changes {
+		if i >= 20 {
+			break
+		}
+		sb.WriteString(fmt.Sprintf("**%s**: %d → %d（%s）\n", change.AtCoderUsername,
+			change.OldRating, change.NewRating, FormatRatingDelta(change.NewRating-change.OldRating)))
+	}
+	return &discordgo.MessageEmbedField{
+		Name:  "📊 レーティング変動",
+		Value: sb.String(),
+	}
+}
+
+// FormatRatingDelta formats a rating change with an explicit sign
+func FormatRatingDelta(delta int) string {
+	if delta > 0 {
+		return fmt.Sprintf("+%d", delta)
+	}
+	if delta == 0 {
+		return "±0"
+	}
+	return fmt.Sprintf("%d", delta)
+}
@@ -0,0 +1,61 @@
+package virtual
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"coding-winner/internal/models"
+)
+
+// MinScheduleInterval is the shortest allowed gap between two runs of a template
+const MinScheduleInterval = 24 * time.Hour
+
+// scheduleParser accepts only the 5 plain fields, without descriptors such as @every or @hourly
+var scheduleParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// ParseSchedule parses a plain 5-field cron expression (minute hour day month weekday).
+// Descriptors, time zone prefixes and schedules that run more often than
+// MinScheduleInterval are rejected.
+func ParseSchedule(spec string) (cron.Schedule, error) {
+	if fields := strings.Fields(spec); len(fields) != 5 || strings.ContainsAny(spec, "@=") {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields", spec)
+	}
+	schedule, err := scheduleParser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+	}
+
+	// Check a year of runs, which covers every day, month and weekday combination
+	prev := schedule.Next(time.Now().In(JST))
+	for end := prev.AddDate(1, 0, 0); prev.Before(end); {
+		next := schedule.Next(prev)
+		if next.IsZero() {
+			break
+		}
+		if next.Sub(prev) < MinScheduleInterval {
+			return nil, fmt.Errorf("invalid schedule %q: runs more often than every %v", spec, MinScheduleInterval)
+		}
+		prev = next
+	}
+	return schedule, nil
+}
+
+// NextOccurrence returns the first start time of a template after now, interpreting the schedule in JST.
+// Like ParseStartTime, the result is in local time.
+func NextOccurrence(template *models.VirtualContestTemplate, now time.Time) (time.Time, error) {
+	schedule, err := ParseSchedule(template.Schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(now.In(JST)).Local(), nil
+}
+
+// DescribeTemplateProblems describes how a template selects its problems
+func DescribeTemplateProblems(template *models.VirtualContestTemplate) string {
+	if len(template.ProblemIDs) > 0 {
+		return fmt.Sprintf("固定%d問", len(template.ProblemIDs))
+	}
+	return fmt.Sprintf("難易度%d-%dから%d問", template.DifficultyMin.Int64, template.DifficultyMax.Int64, template.ProblemCount.Int64)
+}
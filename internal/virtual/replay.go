@@ -0,0 +1,124 @@
+package virtual
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"coding-winner/internal/atcoder"
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// contestURLPattern matches https://atcoder.jp/contests/<contest>
+var contestURLPattern = regexp.MustCompile(`^https?://atcoder\.jp/contests/([^/\s?#]+)`)
+
+// ParseContestID extracts an AtCoder contest ID from an ID or contest URL
+func ParseContestID(input string) string {
+	input = strings.TrimSpace(input)
+	if m := contestURLPattern.FindStringSubmatch(input); m != nil {
+		return m[1]
+	}
+	return strings.ToLower(input)
+}
+
+// ErrReplayNoProblems is returned when a contest to replay has no known problems
+var ErrReplayNoProblems = errors.New("contest has no problems")
+
+// SetupReplay fills a virtual contest with the problems, point values and duration of
+// an AtCoder contest. It returns the original contest, or sql.ErrNoRows if it is unknown.
+func SetupReplay(db queries.UserDB, contest *models.VirtualContest, atcoderContestID string) (*models.Contest, error) {
+	original, err := queries.GetContest(db, atcoderContestID)
+	if err != nil {
+		return nil, err
+	}
+	problemSet, err := queries.GetContestProblemSet(db, original.ContestID)
+	if err != nil {
+		return nil, err
+	}
+	if len(problemSet) == 0 {
+		return nil, ErrReplayNoProblems
+	}
+
+	// Use the original point values only if every problem has one
+	contest.ProblemIDs = nil
+	contest.ProblemPoints = nil
+	var points []float64
+	for _, cp := range problemSet {
+		contest.ProblemIDs = append(contest.ProblemIDs, cp.ProblemID)
+		if cp.Point.Valid {
+			points = append(points, cp.Point.Float64)
+		}
+	}
+	if len(points) == len(problemSet) {
+		contest.ProblemPoints = points
+	}
+
+	contest.Title = fmt.Sprintf("%s（リプレイ）", original.Title)
+	contest.DurationMinutes = original.DurationSeconds / 60
+	contest.ReplayContestID = sql.NullString{String: original.ContestID, Valid: true}
+	return original, nil
+}
+
+// EstimatePerformances estimates each participant's performance in a replayed contest.
+// The estimate is the rating that best explains which problems the participant solved,
+// using the difficulty models of the contest's problems. Participants are omitted
+// when none of the problems has a model.
+func EstimatePerformances(db queries.UserDB, contest *models.VirtualContest, standings []models.VirtualContestStanding) (map[string]int, error) {
+	problems, err := queries.GetProblemsByIDs(db, contest.ProblemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var modeled []*models.Problem
+	for _, p := range problems {
+		if p.Difficulty.Valid && p.Discrimination.Valid {
+			modeled = append(modeled, p)
+		}
+	}
+
+	performances := make(map[string]int)
+	if len(modeled) == 0 {
+		return performances, nil
+	}
+
+	for _, standing := range standings {
+		attempts := make([]atcoder.IRTAttempt, 0, len(modeled))
+		for _, p := range modeled {
+			attempts = append(attempts, atcoder.IRTAttempt{
+				Difficulty:     float64(p.Difficulty.Int64),
+				Discrimination: p.Discrimination.Float64,
+				Solved:         standing.Problems[p.ProblemID].Solved,
+			})
+		}
+		performances[standing.UserID] = int(atcoder.EstimateRating(attempts))
+	}
+	return performances, nil
+}
+
+// BuildPerformanceField builds the embed field listing estimated performances in standings order
+func BuildPerformanceField(standings []models.VirtualContestStanding, performances map[string]int) *discordgo.MessageEmbedField {
+	var sb strings.Builder
+	for i, standing := range standings {
+		if i >= 20 {
+			break
+		}
+		perf, ok := performances[standing.UserID]
+		if !ok {
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("**%s**: %d\n", standing.AtCoderUsername, perf))
+	}
+
+	value := sb.String()
+	if value == "" {
+		value = "難易度データがないため推定できませんでした。"
+	}
+	return &discordgo.MessageEmbedField{
+		Name:  "📈 推定パフォーマンス",
+		Value: value,
+	}
+}
@@ -0,0 +1,265 @@
+package virtual
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"coding-winner/internal/database/queries"
+	"coding-winner/internal/models"
+)
+
+// Penalty added per wrong attempt before an AC under each rule
+var wrongAttemptPenalty = map[string]time.Duration{
+	models.PenaltyRuleAtCoder: 5 * time.Minute,
+	models.PenaltyRuleICPC:    20 * time.Minute,
+}
+
+// maxStandingsLength keeps formatted standings within Discord's message limit
+const maxStandingsLength = 1800
+
+// GetStandings loads the participants and results of a contest and ranks them,
+// aggregating by team if the contest has teams
+func GetStandings(db queries.UserDB, contest *models.VirtualContest) ([]models.VirtualContestStanding, error) {
+	participants, err := queries.GetVirtualContestParticipants(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := queries.GetVirtualContestResults(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	presolved, err := queries.GetPresolvedProblems(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	standings := ComputeStandings(contest, participants, rows, presolved)
+
+	members, err := queries.GetVirtualContestTeamMembers(db, contest.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) > 0 {
+		standings = MergeTeamStandings(contest, standings, members)
+	}
+	return standings, nil
+}
+
+// ParticipantStart returns the time from which a participant's elapsed times are measured
+func ParticipantStart(contest *models.VirtualContest, participant *models.VirtualContestParticipant) time.Time {
+	return contest.StartTime.Time.Add(time.Duration(participant.StartOffsetSeconds) * time.Second)
+}
+
+// ComputeStandings ranks the participants by the contest's penalty rule.
+// Every participant is listed, including those without submissions; results of
+// users who have not joined are ignored. Problems a participant solved before
+// their start are flagged, and under the exclude policy count as unsolved.
+//
+// AtCoder rule: higher total score first, then smaller penalty, where the penalty is the
+// elapsed time of the last AC plus 5 minutes per wrong attempt before an AC.
+//
+// ICPC rule: more solved problems first, then smaller penalty, where the penalty is the sum
+// over solved problems of the elapsed time of the AC plus 20 minutes per wrong attempt.
+func ComputeStandings(contest *models.VirtualContest, participants []*models.VirtualContestParticipantRow, rows []*models.VirtualContestResultRow, presolved []*models.PresolvedProblem) []models.VirtualContestStanding {
+	rule := contest.PenaltyRule
+	if _, ok := wrongAttemptPenalty[rule]; !ok {
+		rule = models.PenaltyRuleAtCoder
+	}
+
+	byUser := make(map[string]*models.VirtualContestStanding, len(participants))
+	starts := make(map[string]time.Time, len(participants))
+	for _, p := range participants {
+		byUser[p.UserID] = &models.VirtualContestStanding{
+			UserID:          p.UserID,
+			AtCoderUsername: p.AtCoderUsername,
+			Problems:        make(map[string]models.VirtualProblemResult),
+		}
+		starts[p.UserID] = ParticipantStart(contest, &p.VirtualContestParticipant)
+	}
+
+	for _, row := range rows {
+		standing, ok := byUser[row.UserID]
+		if !ok {
+			continue
+		}
+
+		result := models.VirtualProblemResult{
+			Solved:        row.Result == "AC",
+			WrongAttempts: row.WrongAttempts,
+		}
+		if result.Solved {
+			result.Elapsed = row.SubmittedAt.Sub(starts[row.UserID])
+			result.SubmittedAt = row.SubmittedAt
+			result.Point = ProblemPoint(contest, row.ProblemID, row.Point)
+		}
+		standing.Problems[row.ProblemID] = result
+	}
+
+	for _, ps := range presolved {
+		standing, ok := byUser[ps.UserID]
+		if !ok {
+			continue
+		}
+		result := standing.Problems[ps.ProblemID]
+		result.Presolved = true
+		if contest.PresolvedPolicy == models.PresolvedExclude {
+			result.Solved = false
+			result.Elapsed = 0
+			result.SubmittedAt = time.Time{}
+			result.Point = 0
+		}
+		standing.Problems[ps.ProblemID] = result
+		standing.HasPresolved = true
+	}
+
+	standings := make([]models.VirtualContestStanding, 0, len(participants))
+	for _, p := range participants {
+		standing := byUser[p.UserID]
+		applyPenalty(standing, rule)
+		standings = append(standings, *standing)
+	}
+
+	sortStandings(standings, rule)
+	return standings
+}
+
+// applyPenalty fills in the solved count, score and penalty of a standing
+func applyPenalty(standing *models.VirtualContestStanding, rule string) {
+	var lastAC, icpcPenalty time.Duration
+	wrongBeforeAC := 0
+	for _, result := range standing.Problems {
+		if !result.Solved {
+			continue
+		}
+		standing.SolvedCount++
+		standing.TotalPoints += result.Point
+		wrongBeforeAC += result.WrongAttempts
+		if result.Elapsed > lastAC {
+			lastAC = result.Elapsed
+		}
+		icpcPenalty += result.Elapsed + time.Duration(result.WrongAttempts)*wrongAttemptPenalty[rule]
+	}
+
+	if rule == models.PenaltyRuleICPC {
+		standing.PenaltyTime = icpcPenalty
+	} else {
+		standing.PenaltyTime = lastAC + time.Duration(wrongBeforeAC)*wrongAttemptPenalty[rule]
+	}
+}
+
+// sortStandings orders standings and assigns ranks, giving ties the same rank
+func sortStandings(standings []models.VirtualContestStanding, rule string) {
+	primary := func(s models.VirtualContestStanding) float64 {
+		if rule == models.PenaltyRuleICPC {
+			return float64(s.SolvedCount)
+		}
+		return s.TotalPoints
+	}
+
+	sort.SliceStable(standings, func(a, b int) bool {
+		if primary(standings[a]) != primary(standings[b]) {
+			return primary(standings[a]) > primary(standings[b])
+		}
+		return standings[a].PenaltyTime < standings[b].PenaltyTime
+	})
+
+	for i := range standings {
+		if i > 0 && primary(standings[i]) == primary(standings[i-1]) &&
+			standings[i].PenaltyTime == standings[i-1].PenaltyTime {
+			standings[i].Rank = standings[i-1].Rank
+		} else {
+			standings[i].Rank = i + 1
+		}
+	}
+}
+
+// FormatStandings formats standings as a table with one cell per problem.
+// Solved cells show the elapsed time and wrong attempts; unsolved attempted cells show wrong attempts only.
+func FormatStandings(contest *models.VirtualContest, standings []models.VirtualContestStanding) string {
+	if len(standings) == 0 {
+		return "参加者がいません。"
+	}
+
+	header := fmt.Sprintf("%-4s %-16s %6s %8s", "#", "User", "Score", "Time")
+	for i := range contest.ProblemIDs {
+		header += fmt.Sprintf(" %-9s", ProblemLabel(i))
+	}
+
+	hasPresolved := false
+	var sb strings.Builder
+	sb.WriteString("```\n")
+	sb.WriteString(header + "\n")
+	for idx, standing := range standings {
+		name := truncateName(standing.AtCoderUsername, 16)
+		if standing.HasPresolved {
+			name = "*" + truncateName(standing.AtCoderUsername, 15)
+			hasPresolved = true
+		}
+		line := fmt.Sprintf("%-4d %-16s %6.0f %8s", standing.Rank, name,
+			standing.TotalPoints, FormatElapsed(standing.PenaltyTime))
+		for _, pid := range contest.ProblemIDs {
+			result, attempted := standing.Problems[pid]
+			line += fmt.Sprintf(" %-9s", formatProblemCell(result, attempted))
+		}
+
+		if sb.Len()+len(line) > maxStandingsLength {
+			sb.WriteString(fmt.Sprintf("…ほか%d人\n", len(standings)-idx))
+			break
+		}
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("```")
+	if hasPresolved {
+		sb.WriteString("\n* 開始前にAC済みの問題あり")
+		if contest.PresolvedPolicy == models.PresolvedExclude {
+			sb.WriteString("（その問題は得点に含めません）")
+		}
+	}
+	return sb.String()
+}
+
+// formatProblemCell formats one problem cell of the standings table.
+// Problems solved before the start are prefixed with "*".
+func formatProblemCell(result models.VirtualProblemResult, attempted bool) string {
+	if result.Presolved {
+		result.Presolved = false
+		return "*" + formatProblemCell(result, result.Solved || result.WrongAttempts > 0)
+	}
+
+	switch {
+	case result.Solved && result.WrongAttempts > 0:
+		return fmt.Sprintf("%s(%d)", FormatElapsed(result.Elapsed), result.WrongAttempts)
+	case result.Solved:
+		return FormatElapsed(result.Elapsed)
+	case attempted && result.WrongAttempts > 0:
+		return fmt.Sprintf("-(%d)", result.WrongAttempts)
+	case attempted:
+		return "-"
+	default:
+		return ""
+	}
+}
+
+// ProblemLabel returns the task letter for the i-th problem (A, B, ...)
+func ProblemLabel(i int) string {
+	if i < 26 {
+		return string(rune('A' + i))
+	}
+	return fmt.Sprintf("P%d", i+1)
+}
+
+// FormatElapsed formats a duration as M:SS, with minutes allowed to exceed 60
+func FormatElapsed(d time.Duration) string {
+	seconds := int(d.Seconds())
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
+
+// truncateName shortens a name to at most n characters
+func truncateName(name string, n int) string {
+	if utf8.RuneCountInString(name) <= n {
+		return name
+	}
+	return string([]rune(name)[:n-1]) + "…"
+}
@@ -0,0 +1,84 @@
+package virtual
+
+import (
+	"fmt"
+	"strings"
+
+	"coding-winner/internal/models"
+)
+
+// TeamStandingID returns the standing UserID used for a team, distinguishing it from Discord IDs
+func TeamStandingID(teamID int) string {
+	return fmt.Sprintf("team:%d", teamID)
+}
+
+// IsTeamStanding reports whether a standing belongs to a team rather than a member
+func IsTeamStanding(standing models.VirtualContestStanding) bool {
+	return strings.HasPrefix(standing.UserID, "team:")
+}
+
+// MergeTeamStandings aggregates individual standings into team standings and re-ranks them.
+// A problem counts once per team, with the first AC in real time by any member, and its
+// elapsed time is measured from the contest start rather than the member's own start.
+// For a solved problem the wrong attempts are those of the member who solved it first; for
+// an unsolved problem they are summed over the members. Participants without a team stay
+// in the standings on their own.
+func MergeTeamStandings(contest *models.VirtualContest, standings []models.VirtualContestStanding, members []*models.VirtualContestTeamMemberRow) []models.VirtualContestStanding {
+	rule := contest.PenaltyRule
+	if _, ok := wrongAttemptPenalty[rule]; !ok {
+		rule = models.PenaltyRuleAtCoder
+	}
+
+	teamOf := make(map[string]*models.VirtualContestTeamMemberRow, len(members))
+	for _, m := range members {
+		teamOf[m.UserID] = m
+	}
+
+	byTeam := make(map[int]*models.VirtualContestStanding)
+	var teamOrder []int
+	var merged []models.VirtualContestStanding
+	for _, standing := range standings {
+		member, ok := teamOf[standing.UserID]
+		if !ok {
+			merged = append(merged, standing)
+			continue
+		}
+
+		team, ok := byTeam[member.TeamID]
+		if !ok {
+			team = &models.VirtualContestStanding{
+				UserID:          TeamStandingID(member.TeamID),
+				AtCoderUsername: member.TeamName,
+				Problems:        make(map[string]models.VirtualProblemResult),
+			}
+			byTeam[member.TeamID] = team
+			teamOrder = append(teamOrder, member.TeamID)
+		}
+
+		team.HasPresolved = team.HasPresolved || standing.HasPresolved
+		for problemID, result := range standing.Problems {
+			if result.Solved && !result.SubmittedAt.IsZero() {
+				result.Elapsed = result.SubmittedAt.Sub(contest.StartTime.Time)
+			}
+			current, exists := team.Problems[problemID]
+			switch {
+			case !exists:
+				team.Problems[problemID] = result
+			case result.Solved && (!current.Solved || result.SubmittedAt.Before(current.SubmittedAt)):
+				team.Problems[problemID] = result
+			case !result.Solved && !current.Solved:
+				current.WrongAttempts += result.WrongAttempts
+				team.Problems[problemID] = current
+			}
+		}
+	}
+
+	for _, teamID := range teamOrder {
+		team := byTeam[teamID]
+		applyPenalty(team, rule)
+		merged = append(merged, *team)
+	}
+
+	sortStandings(merged, rule)
+	return merged
+}
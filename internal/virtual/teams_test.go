@@ -0,0 +1,62 @@
+package virtual
+
+import (
+	"database/sql"
+	"testing"
+	"time"
+
+	"coding-winner/internal/models"
+)
+
+func TestMergeTeamStandingsUsesRealTimeFirstAC(t *testing.T) {
+	start := time.Unix(1704542400, 0)
+	contest := &models.VirtualContest{
+		ID:          1,
+		StartTime:   sql.NullTime{Time: start, Valid: true},
+		PenaltyRule: models.PenaltyRuleAtCoder,
+		ProblemIDs:  []string{"abc300_a"},
+	}
+
+	// u2 joined 30 minutes late, so their AC has the smaller elapsed time of their own
+	// although u1 solved the problem 20 minutes earlier in real time
+	participants := []*models.VirtualContestParticipantRow{
+		{VirtualContestParticipant: models.VirtualContestParticipant{ContestID: 1, UserID: "u1"}, AtCoderUsername: "alice"},
+		{VirtualContestParticipant: models.VirtualContestParticipant{ContestID: 1, UserID: "u2", StartOffsetSeconds: 30 * 60}, AtCoderUsername: "bob"},
+	}
+	rows := []*models.VirtualContestResultRow{
+		{VirtualContestSubmission: models.VirtualContestSubmission{
+			ContestID: 1, UserID: "u1", ProblemID: "abc300_a", Result: "AC", Point: 100,
+			SubmittedAt: start.Add(20 * time.Minute), WrongAttempts: 1,
+		}},
+		{VirtualContestSubmission: models.VirtualContestSubmission{
+			ContestID: 1, UserID: "u2", ProblemID: "abc300_a", Result: "AC", Point: 100,
+			SubmittedAt: start.Add(40 * time.Minute),
+		}},
+	}
+	members := []*models.VirtualContestTeamMemberRow{
+		{VirtualContestTeamMember: models.VirtualContestTeamMember{ContestID: 1, TeamID: 7, UserID: "u1"}, TeamName: "team"},
+		{VirtualContestTeamMember: models.VirtualContestTeamMember{ContestID: 1, TeamID: 7, UserID: "u2"}, TeamName: "team"},
+	}
+
+	standings := ComputeStandings(contest, participants, rows, nil)
+	for _, standing := range standings {
+		if standing.UserID == "u2" && standing.Problems["abc300_a"].Elapsed != 10*time.Minute {
+			t.Fatalf("bob's own elapsed = %v, want 10m", standing.Problems["abc300_a"].Elapsed)
+		}
+	}
+
+	merged := MergeTeamStandings(contest, standings, members)
+	if len(merged) != 1 {
+		t.Fatalf("got %d standings, want 1", len(merged))
+	}
+	result := merged[0].Problems["abc300_a"]
+	if result.Elapsed != 20*time.Minute {
+		t.Errorf("team elapsed = %v, want 20m", result.Elapsed)
+	}
+	if result.WrongAttempts != 1 {
+		t.Errorf("team wrong attempts = %d, want 1", result.WrongAttempts)
+	}
+	if want := 25 * time.Minute; merged[0].PenaltyTime != want {
+		t.Errorf("team penalty = %v, want %v", merged[0].PenaltyTime, want)
+	}
+}
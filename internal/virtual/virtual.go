@@ -0,0 +1,121 @@
+package virtual
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"coding-winner/internal/models"
+)
+
+// JST is the time zone used for displaying and parsing contest times
+var JST = time.FixedZone("JST", 9*60*60)
+
+// StartTimeLayout is the accepted format for user-specified start times (JST)
+const StartTimeLayout = "2006-01-02 15:04"
+
+var statusLabels = map[string]string{
+	models.VirtualContestDraft:     "📝 下書き",
+	models.VirtualContestScheduled: "⏰ 開始予定",
+	models.VirtualContestRunning:   "🏃 開催中",
+	models.VirtualContestFinished:  "🏁 終了",
+	models.VirtualContestCancelled: "🚫 中止",
+}
+
+// ParseStartTime parses a start time given in JST.
+// The result is in local time, like time.Now(), because TIMESTAMP columns drop the zone offset.
+func ParseStartTime(value string) (time.Time, error) {
+	t, err := time.ParseInLocation(StartTimeLayout, strings.TrimSpace(value), JST)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.Local(), nil
+}
+
+// EndTime returns the end time of a contest that has a start time
+func EndTime(contest *models.VirtualContest) time.Time {
+	return contest.StartTime.Time.Add(time.Duration(contest.DurationMinutes) * time.Minute)
+}
+
+// StatusLabel returns a human-readable label for a contest state
+func StatusLabel(status string) string {
+	if label, ok := statusLabels[status]; ok {
+		return label
+	}
+	return status
+}
+
+// ProblemURL builds the AtCoder task URL of a problem from its stored contest ID
+func ProblemURL(problem *models.Problem) string {
+	contestID := problem.ContestID.String
+	if !problem.ContestID.Valid {
+		contestID = strings.Split(problem.ProblemID, "_")[0]
+	}
+	return fmt.Sprintf("https://atcoder.jp/contests/%s/tasks/%s", contestID, problem.ProblemID)
+}
+
+// NoPointOverride marks a problem in ProblemPoints that keeps its AtCoder point value
+const NoPointOverride = -1
+
+// ProblemPoint returns the contest's point value for a problem, or fallback if the contest does not set one
+func ProblemPoint(contest *models.VirtualContest, problemID string, fallback float64) float64 {
+	if len(contest.ProblemPoints) != len(contest.ProblemIDs) {
+		return fallback
+	}
+	for i, pid := range contest.ProblemIDs {
+		if pid == problemID && contest.ProblemPoints[i] >= 0 {
+			return contest.ProblemPoints[i]
+		}
+	}
+	return fallback
+}
+
+// FormatProblemList formats problems as a list labelled A, B, ..., with point values if the contest sets them
+func FormatProblemList(contest *models.VirtualContest, problems []*models.Problem) string {
+	hasPoints := len(contest.ProblemPoints) == len(problems)
+
+	var sb strings.Builder
+	for i, p := range problems {
+		sb.WriteString(fmt.Sprintf("%s. [%s](%s)", ProblemLabel(i), p.Title, ProblemURL(p)))
+		if hasPoints && contest.ProblemPoints[i] >= 0 {
+			sb.WriteString(fmt.Sprintf("（%.0f点）", contest.ProblemPoints[i]))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// FormatStartMessage builds the announcement posted when a contest starts
+func FormatStartMessage(contest *models.VirtualContest, problems []*models.Problem) string {
+	return fmt.Sprintf("🏁 **バーチャルコンテスト開始！**\n\n"+
+		"**タイトル**: %s\n"+
+		"**時間**: %d分\n"+
+		"**終了時刻**: %s\n\n"+
+		"**問題**:\n%s\n"+
+		"頑張ってください！",
+		contest.Title, contest.DurationMinutes, EndTime(contest).In(JST).Format("15:04"), FormatProblemList(contest, problems))
+}
+
+// FormatCreatedMessage builds the body of the announcement posted when a contest is created:
+// its ID, duration and problem count, any extraLines, and how and when it starts.
+// Callers put their own headline above it and attach ParticipationButtons.
+func FormatCreatedMessage(contest *models.VirtualContest, extraLines []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("コンテストID: %d\n時間: %d分\n問題数: %d\n",
+		contest.ID, contest.DurationMinutes, len(contest.ProblemIDs)))
+	for _, line := range extraLines {
+		sb.WriteString(line + "\n")
+	}
+	sb.WriteString("\n")
+
+	switch {
+	case contest.Status == models.VirtualContestRunning:
+		sb.WriteString(fmt.Sprintf("開催中です（%s 終了）。", EndTime(contest).In(JST).Format("15:04")))
+	case contest.StartTime.Valid:
+		sb.WriteString(fmt.Sprintf("%s に自動で開始します。", contest.StartTime.Time.In(JST).Format(StartTimeLayout)))
+	default:
+		sb.WriteString(fmt.Sprintf("`/virtual-start %d` で開始してください。", contest.ID))
+	}
+	sb.WriteString("\n参加する人は下のボタンを押してください。")
+	return sb.String()
+}
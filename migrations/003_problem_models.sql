@@ -0,0 +1,6 @@
+-- 003_problem_models.sql
+-- Store IRT parameters from AtCoder Problems' problem-models
+
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS slope DOUBLE PRECISION;
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS intercept DOUBLE PRECISION;
+ALTER TABLE problems ADD COLUMN IF NOT EXISTS discrimination DOUBLE PRECISION;
@@ -0,0 +1,22 @@
+-- 004_goals.sql
+-- Personal goals
+
+CREATE TABLE IF NOT EXISTS goals (
+    id SERIAL PRIMARY KEY,
+    user_id VARCHAR(20) REFERENCES users(discord_id) ON DELETE CASCADE,
+    server_id VARCHAR(20) NOT NULL,
+    channel_id VARCHAR(20) NOT NULL,
+    kind VARCHAR(20) NOT NULL, -- new-ac, color-ac, rating
+    target INT NOT NULL,
+    color VARCHAR(20),
+    period_start TIMESTAMP,
+    period_end TIMESTAMP,
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_goals_user
+ON goals(user_id);
+
+CREATE INDEX IF NOT EXISTS idx_goals_completed
+ON goals(server_id, completed_at);
@@ -0,0 +1,16 @@
+-- 005_badges.sql
+-- Achievement badges
+
+CREATE TABLE IF NOT EXISTS user_badges (
+    user_id VARCHAR(20) REFERENCES users(discord_id) ON DELETE CASCADE,
+    badge_key VARCHAR(50) NOT NULL,
+    unlocked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, badge_key)
+);
+
+-- Badge announcement configuration per server
+CREATE TABLE IF NOT EXISTS badge_config (
+    server_id VARCHAR(20) PRIMARY KEY,
+    channel_id VARCHAR(20) NOT NULL,
+    enabled BOOLEAN DEFAULT true
+);
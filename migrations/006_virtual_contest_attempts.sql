@@ -0,0 +1,4 @@
+-- 006_virtual_contest_attempts.sql
+-- Track wrong attempts before the first AC in virtual contests
+
+ALTER TABLE virtual_contest_submissions ADD COLUMN IF NOT EXISTS wrong_attempts INT DEFAULT 0;
@@ -0,0 +1,27 @@
+-- 007_virtual_contest_status.sql
+-- Explicit lifecycle states for virtual contests
+
+ALTER TABLE virtual_contests ALTER COLUMN start_time DROP NOT NULL;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'virtual_contests' AND column_name = 'status'
+    ) THEN
+        ALTER TABLE virtual_contests ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft';
+
+        -- Existing contests had their start time stamped at creation,
+        -- so only those already over can be considered finished
+        UPDATE virtual_contests
+        SET status = 'finished'
+        WHERE start_time + (duration_minutes || ' minutes')::INTERVAL <= CURRENT_TIMESTAMP;
+
+        UPDATE virtual_contests
+        SET start_time = NULL
+        WHERE status = 'draft';
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_virtual_contests_status
+ON virtual_contests(status);
@@ -0,0 +1,4 @@
+-- 008_virtual_contest_penalty.sql
+-- Per-contest penalty rule (atcoder or icpc)
+
+ALTER TABLE virtual_contests ADD COLUMN IF NOT EXISTS penalty_rule VARCHAR(10) NOT NULL DEFAULT 'atcoder';
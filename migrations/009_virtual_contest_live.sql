@@ -0,0 +1,20 @@
+-- 009_virtual_contest_live.sql
+-- Live standings message, solve feed thread and final results tracking
+
+ALTER TABLE virtual_contests ADD COLUMN IF NOT EXISTS standings_message_id VARCHAR(20);
+ALTER TABLE virtual_contests ADD COLUMN IF NOT EXISTS thread_id VARCHAR(20);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_name = 'virtual_contests' AND column_name = 'results_posted_at'
+    ) THEN
+        ALTER TABLE virtual_contests ADD COLUMN results_posted_at TIMESTAMP;
+
+        -- Do not post results for contests that finished before this feature existed
+        UPDATE virtual_contests
+        SET results_posted_at = CURRENT_TIMESTAMP
+        WHERE status = 'finished';
+    END IF;
+END $$;
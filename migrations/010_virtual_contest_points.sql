@@ -0,0 +1,4 @@
+-- 010_virtual_contest_points.sql
+-- Per-contest point values, parallel to problem_ids (NULL = use AtCoder's points)
+
+ALTER TABLE virtual_contests ADD COLUMN IF NOT EXISTS problem_points DOUBLE PRECISION[];
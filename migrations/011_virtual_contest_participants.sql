@@ -0,0 +1,11 @@
+-- 011_virtual_contest_participants.sql
+-- Members who joined a virtual contest. start_offset_seconds is how late after the
+-- contest start the member joined; their elapsed times are measured from that point.
+
+CREATE TABLE IF NOT EXISTS virtual_contest_participants (
+    contest_id INT REFERENCES virtual_contests(id) ON DELETE CASCADE,
+    user_id VARCHAR(20) REFERENCES users(discord_id) ON DELETE CASCADE,
+    joined_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    start_offset_seconds INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (contest_id, user_id)
+);
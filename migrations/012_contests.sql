@@ -0,0 +1,21 @@
+-- 012_contests.sql
+-- AtCoder contests and their problem sets, for replaying past contests
+
+CREATE TABLE IF NOT EXISTS contests (
+    contest_id VARCHAR(50) PRIMARY KEY,
+    title VARCHAR(200) NOT NULL,
+    start_time TIMESTAMP NOT NULL,
+    duration_seconds INT NOT NULL,
+    rate_change VARCHAR(50)
+);
+
+CREATE TABLE IF NOT EXISTS contest_problems (
+    contest_id VARCHAR(50) REFERENCES contests(contest_id) ON DELETE CASCADE,
+    problem_id VARCHAR(50) NOT NULL,
+    problem_index VARCHAR(10) NOT NULL,
+    point FLOAT,
+    PRIMARY KEY (contest_id, problem_id)
+);
+
+-- Set when a virtual contest replays a real AtCoder contest
+ALTER TABLE virtual_contests ADD COLUMN IF NOT EXISTS replay_contest_id VARCHAR(50);
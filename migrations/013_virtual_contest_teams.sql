@@ -0,0 +1,17 @@
+-- 013_virtual_contest_teams.sql
+-- Teams for team virtual contests. A user belongs to at most one team per contest.
+
+CREATE TABLE IF NOT EXISTS virtual_contest_teams (
+    id SERIAL PRIMARY KEY,
+    contest_id INT REFERENCES virtual_contests(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(contest_id, name)
+);
+
+CREATE TABLE IF NOT EXISTS virtual_contest_team_members (
+    contest_id INT REFERENCES virtual_contests(id) ON DELETE CASCADE,
+    team_id INT REFERENCES virtual_contest_teams(id) ON DELETE CASCADE,
+    user_id VARCHAR(20) REFERENCES users(discord_id) ON DELETE CASCADE,
+    PRIMARY KEY (contest_id, user_id)
+);
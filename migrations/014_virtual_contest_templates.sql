@@ -0,0 +1,23 @@
+-- 014_virtual_contest_templates.sql
+-- Recurring virtual contests. Each occurrence of the cron schedule (JST) is
+-- instantiated into virtual_contests ahead of time and started automatically.
+-- Problems are either a fixed list (problem_ids) or picked by difficulty range.
+
+CREATE TABLE IF NOT EXISTS virtual_contest_templates (
+    id SERIAL PRIMARY KEY,
+    server_id VARCHAR(20) NOT NULL,
+    channel_id VARCHAR(20) NOT NULL,
+    created_by VARCHAR(20),
+    title VARCHAR(200) NOT NULL,
+    schedule VARCHAR(100) NOT NULL,
+    duration_minutes INT NOT NULL,
+    problem_ids TEXT[],
+    problem_count INT,
+    difficulty_min INT,
+    difficulty_max INT,
+    penalty_rule VARCHAR(10) NOT NULL DEFAULT 'atcoder',
+    announce_minutes INT NOT NULL DEFAULT 60,
+    last_occurrence TIMESTAMP,
+    enabled BOOLEAN DEFAULT true,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
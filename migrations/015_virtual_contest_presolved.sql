@@ -0,0 +1,5 @@
+-- 015_virtual_contest_presolved.sql
+-- How to treat problems a participant solved before the contest started:
+-- 'warn' flags them in the standings and warns at join; 'exclude' also removes them from the score
+
+ALTER TABLE virtual_contests ADD COLUMN IF NOT EXISTS presolved_policy VARCHAR(10) NOT NULL DEFAULT 'warn';
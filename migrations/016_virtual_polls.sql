@@ -0,0 +1,37 @@
+-- 016_virtual_polls.sql
+-- Button votes choosing the next virtual contest. When the deadline passes the
+-- winning option is turned into a virtual contest (created_contest_id).
+
+CREATE TABLE IF NOT EXISTS virtual_polls (
+    id SERIAL PRIMARY KEY,
+    server_id VARCHAR(20) NOT NULL,
+    channel_id VARCHAR(20) NOT NULL,
+    message_id VARCHAR(20),
+    created_by VARCHAR(20),
+    mode VARCHAR(10) NOT NULL,
+    duration_minutes INT NOT NULL,
+    penalty_rule VARCHAR(10) NOT NULL DEFAULT 'atcoder',
+    start_time TIMESTAMP,
+    deadline TIMESTAMP NOT NULL,
+    closed BOOLEAN NOT NULL DEFAULT false,
+    created_contest_id INT REFERENCES virtual_contests(id) ON DELETE SET NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS virtual_poll_options (
+    poll_id INT REFERENCES virtual_polls(id) ON DELETE CASCADE,
+    option_index INT NOT NULL,
+    label TEXT NOT NULL,
+    problem_ids TEXT[],
+    problem_points DOUBLE PRECISION[],
+    replay_contest_id VARCHAR(50),
+    PRIMARY KEY (poll_id, option_index)
+);
+
+CREATE TABLE IF NOT EXISTS virtual_poll_votes (
+    poll_id INT REFERENCES virtual_polls(id) ON DELETE CASCADE,
+    user_id VARCHAR(20) NOT NULL,
+    option_index INT NOT NULL,
+    voted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (poll_id, user_id)
+);
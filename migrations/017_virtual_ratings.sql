@@ -0,0 +1,24 @@
+-- 017_virtual_ratings.sql
+-- Server-local Elo rating updated from the final standings of each virtual contest.
+
+CREATE TABLE IF NOT EXISTS virtual_ratings (
+    server_id VARCHAR(20) NOT NULL,
+    user_id VARCHAR(20) REFERENCES users(discord_id) ON DELETE CASCADE,
+    rating INT NOT NULL,
+    contests INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (server_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS virtual_rating_history (
+    contest_id INT REFERENCES virtual_contests(id) ON DELETE CASCADE,
+    user_id VARCHAR(20) REFERENCES users(discord_id) ON DELETE CASCADE,
+    server_id VARCHAR(20) NOT NULL,
+    contest_rank INT NOT NULL,
+    old_rating INT NOT NULL,
+    new_rating INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (contest_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_virtual_rating_history_user ON virtual_rating_history(server_id, user_id, created_at);
@@ -0,0 +1,17 @@
+-- 018_user_verification.sql
+-- AtCoder account ownership verification. Members prove ownership by putting a
+-- token in the affiliation field of their AtCoder profile; unverified members are
+-- left out of rankings. Members registered before verification existed are kept
+-- as verified (the column is added with DEFAULT true, then the default changes).
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verified BOOLEAN NOT NULL DEFAULT true;
+ALTER TABLE users ALTER COLUMN verified SET DEFAULT false;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS verified_at TIMESTAMP;
+
+-- Pending ownership claims, one per member
+CREATE TABLE IF NOT EXISTS user_verifications (
+    discord_id VARCHAR(20) PRIMARY KEY,
+    atcoder_username VARCHAR(50) NOT NULL,
+    token VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,12 @@
+-- 019_submission_backfills.sql
+-- Cursor of each user's full submission history backfill, so that an interrupted
+-- backfill resumes from the last saved page.
+
+CREATE TABLE IF NOT EXISTS submission_backfills (
+    user_id VARCHAR(20) PRIMARY KEY REFERENCES users(discord_id) ON DELETE CASCADE,
+    atcoder_username VARCHAR(50) NOT NULL,
+    from_second BIGINT NOT NULL DEFAULT 0,
+    fetched INT NOT NULL DEFAULT 0,
+    completed_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
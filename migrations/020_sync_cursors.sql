@@ -0,0 +1,9 @@
+-- 020_sync_cursors.sql
+-- Cursors of syncs that are not tied to a single user, such as the global
+-- recent-submissions feed.
+
+CREATE TABLE IF NOT EXISTS sync_cursors (
+    name VARCHAR(50) PRIMARY KEY,
+    from_second BIGINT NOT NULL,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,13 @@
+-- 021_user_sync_state.sql
+-- Per-user submission sync cursor and health. last_synced_epoch is exclusive: every
+-- submission before it has been synced.
+
+CREATE TABLE IF NOT EXISTS user_sync_state (
+    user_id VARCHAR(20) PRIMARY KEY REFERENCES users(discord_id) ON DELETE CASCADE,
+    last_synced_epoch BIGINT,
+    last_success_at TIMESTAMP,
+    last_error TEXT,
+    last_error_at TIMESTAMP,
+    consecutive_failures INT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);